@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lvlcn-t/go-kit/config"
+	"github.com/lvlcn-t/go-kit/executors"
+)
+
+func main() {
+	// Load the policy from the file. The top-level fields can be overridden with environment variables,
+	// e.g. POLICY_TIMEOUT=20s.
+	config.SetName("policy")
+	policy, err := config.Load[executors.Policy]("./config.yaml")
+	if err != nil {
+		panic(err)
+	}
+
+	// Validate the policy before applying it
+	err = policy.Validate()
+	if err != nil {
+		panic(err)
+	}
+
+	// Apply the policy to a task
+	task := executors.Effector(func(ctx context.Context) error {
+		fmt.Println("Doing something")
+		return nil
+	}).WithPolicy(&policy)
+
+	err = task.Do(context.Background())
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lvlcn-t/go-kit/config"
+	"github.com/lvlcn-t/go-kit/executors"
+)
+
+func TestLoadPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		env     map[string]string
+		want    executors.Policy
+		wantErr bool
+	}{
+		{
+			name: "from file",
+			file: `
+timeout: 10s
+attemptTimeout: 2s
+retry:
+  maxRetries: 3
+  backoff: decorrelated-jitter
+  initialDelay: 100ms
+  maxDelay: 1s
+circuitBreaker:
+  maxFailures: 5
+  resetTimeout: 30s
+protection: true
+`,
+			want: executors.Policy{
+				Timeout:        10 * time.Second,
+				AttemptTimeout: 2 * time.Second,
+				Retry: &executors.RetryPolicy{
+					MaxRetries:   3,
+					Backoff:      executors.BackoffDecorrelatedJitter,
+					InitialDelay: 100 * time.Millisecond,
+					MaxDelay:     time.Second,
+				},
+				CircuitBreaker: &executors.CircuitBreakerPolicy{
+					MaxFailures:  5,
+					ResetTimeout: 30 * time.Second,
+				},
+				Protection: true,
+			},
+		},
+		{
+			name: "overridden by env",
+			file: `
+timeout: 10s
+retry:
+  maxRetries: 3
+  backoff: full-jitter
+`,
+			env: map[string]string{
+				"POLICY_TEST_TIMEOUT":        "20s",
+				"POLICY_TEST_ATTEMPTTIMEOUT": "500ms",
+			},
+			want: executors.Policy{
+				Timeout:        20 * time.Second,
+				AttemptTimeout: 500 * time.Millisecond,
+				Retry: &executors.RetryPolicy{
+					MaxRetries: 3,
+					Backoff:    executors.BackoffFullJitter,
+				},
+			},
+		},
+		{
+			name: "unsupported backoff",
+			file: `
+retry:
+  maxRetries: 3
+  backoff: fibonacci
+`,
+			want: executors.Policy{
+				Retry: &executors.RetryPolicy{
+					MaxRetries: 3,
+					Backoff:    "fibonacci",
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.SetName("policy-test")
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(tt.file), 0o600); err != nil {
+				t.Fatalf("os.WriteFile() error = %v", err)
+			}
+
+			got, err := config.Load[executors.Policy](path)
+			if err != nil {
+				t.Fatalf("config.Load() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("config.Load() = %+v, want %+v", got, tt.want)
+			}
+			if err := got.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Policy.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			// The policy can be loaded as pointer as well.
+			ptr, err := config.Load[*executors.Policy](path)
+			if err != nil {
+				t.Fatalf("config.Load() error = %v", err)
+			}
+			if !reflect.DeepEqual(*ptr, got) {
+				t.Errorf("config.Load() = %+v, want %+v", *ptr, got)
+			}
+		})
+	}
+}
@@ -9,7 +9,7 @@ require (
 	github.com/lvlcn-t/go-kit/config v0.3.0
 	github.com/lvlcn-t/go-kit/dependency v0.1.0
 	github.com/lvlcn-t/go-kit/env v0.0.0-00010101000000-000000000000
-	github.com/lvlcn-t/go-kit/executors v0.3.0
+	github.com/lvlcn-t/go-kit/executors v0.4.0
 	github.com/lvlcn-t/go-kit/metrics v0.3.0
 	github.com/lvlcn-t/go-kit/rest v0.1.0
 	github.com/lvlcn-t/loggerhead v0.3.1
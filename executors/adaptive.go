@@ -0,0 +1,269 @@
+package executors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// ErrLimitExceeded is the error returned when an [AdaptiveLimiter] rejects a call because its concurrency limit is reached.
+type ErrLimitExceeded struct {
+	// Limit is the concurrency limit at the time the call was rejected.
+	Limit int
+}
+
+// Error returns the error message.
+func (e ErrLimitExceeded) Error() string {
+	return fmt.Sprintf("concurrency limit of %d exceeded", e.Limit)
+}
+
+// LimitAlgorithm adjusts the concurrency limit of an [AdaptiveLimiter] based on the outcome of calls.
+// The algorithm is called under the lock of its limiter, so an instance must not be shared between limiters.
+type LimitAlgorithm interface {
+	// Update returns the new limit after a call with the given outcome.
+	// The inFlight calls are the calls that were running when the call started, including the call itself.
+	Update(limit float64, inFlight int, o Outcome) float64
+}
+
+// AIMDOptions are the options of the [AIMD] algorithm.
+type AIMDOptions struct {
+	// Increase is added to the limit after a successful call. Defaults to 1.
+	Increase float64
+	// BackoffRatio is multiplied with the limit after a failed or slow call. Defaults to 0.9.
+	BackoffRatio float64
+	// LatencyThreshold is the duration above which a call is treated like a failure. Zero disables the threshold.
+	LatencyThreshold time.Duration
+}
+
+// AIMD returns a [LimitAlgorithm] that additively increases the limit after successful calls
+// and multiplicatively decreases it after failed or slow calls.
+// The limit only grows while at least half of it is used.
+func AIMD(opts AIMDOptions) LimitAlgorithm {
+	if opts.Increase <= 0 {
+		opts.Increase = 1
+	}
+	if opts.BackoffRatio <= 0 || opts.BackoffRatio >= 1 {
+		opts.BackoffRatio = 0.9
+	}
+	return &aimd{opts: opts}
+}
+
+// aimd is the [LimitAlgorithm] returned by [AIMD].
+type aimd struct {
+	opts AIMDOptions
+}
+
+// Update implements [LimitAlgorithm].
+func (a *aimd) Update(limit float64, inFlight int, o Outcome) float64 {
+	if o.Failed || (a.opts.LatencyThreshold > 0 && o.Duration > a.opts.LatencyThreshold) {
+		return limit * a.opts.BackoffRatio
+	}
+	if float64(inFlight)*2 >= limit {
+		return limit + a.opts.Increase
+	}
+	return limit
+}
+
+// GradientOptions are the options of the [Gradient] algorithm.
+type GradientOptions struct {
+	// Tolerance is the factor by which the latency may exceed the long-term latency before the limit is decreased. Defaults to 1.5.
+	Tolerance float64
+	// Smoothing is the weight of a new limit compared to the current one. Defaults to 0.2.
+	Smoothing float64
+	// Window is the number of calls the long-term latency is averaged over. Defaults to 100.
+	Window int
+}
+
+// Gradient returns a [LimitAlgorithm] that compares the latency of every call with the long-term average latency.
+// The limit grows while the latency is within the tolerance of the average and shrinks once calls queue up downstream.
+// Failed calls shrink the limit like the maximum latency gradient.
+func Gradient(opts GradientOptions) LimitAlgorithm {
+	if opts.Tolerance < 1 {
+		opts.Tolerance = 1.5
+	}
+	if opts.Smoothing <= 0 || opts.Smoothing > 1 {
+		opts.Smoothing = 0.2
+	}
+	if opts.Window < 1 {
+		opts.Window = 100
+	}
+	return &gradient{opts: opts}
+}
+
+// gradient is the [LimitAlgorithm] returned by [Gradient].
+type gradient struct {
+	opts GradientOptions
+	// longRTT is the exponential moving average of the latency in nanoseconds.
+	longRTT float64
+}
+
+// minGradient is the lowest factor the [Gradient] algorithm decreases the limit by.
+const minGradient = 0.5
+
+// Update implements [LimitAlgorithm].
+func (g *gradient) Update(limit float64, inFlight int, o Outcome) float64 {
+	rtt := float64(o.Duration)
+	if g.longRTT == 0 {
+		g.longRTT = rtt
+	} else {
+		g.longRTT += (rtt - g.longRTT) / float64(g.opts.Window)
+	}
+
+	factor := minGradient
+	if !o.Failed {
+		// Don't grow a limit that isn't used.
+		if float64(inFlight)*2 < limit && rtt <= g.longRTT {
+			return limit
+		}
+		factor = 1
+		if rtt > 0 {
+			factor = max(minGradient, min(1, g.opts.Tolerance*g.longRTT/rtt))
+		}
+	}
+
+	// The square root of the limit allows a few calls to queue up, so the limit can grow.
+	newLimit := limit*factor + math.Sqrt(limit)
+	if o.Failed {
+		newLimit = limit * factor
+	}
+	return limit*(1-g.opts.Smoothing) + newLimit*g.opts.Smoothing
+}
+
+// AdaptiveOptions are the options of an [AdaptiveLimiter].
+type AdaptiveOptions struct {
+	// InitialLimit is the concurrency limit to start with. Defaults to 10.
+	InitialLimit int
+	// MinLimit is the lowest the limit can get. Defaults to 1.
+	MinLimit int
+	// MaxLimit is the highest the limit can get. Defaults to 1000.
+	MaxLimit int
+	// Algorithm adjusts the limit. Defaults to [AIMD] with its default options.
+	Algorithm LimitAlgorithm
+	// IsFailure reports whether an error counts as failure for the algorithm. Defaults to [DefaultIsFailure].
+	// Permanent errors and canceled calls never count as failure.
+	IsFailure func(error) bool
+	// Clock measures the latency of the calls. Defaults to [RealClock].
+	Clock Clock
+}
+
+// AdaptiveLimiter limits the number of concurrent calls of an effector and adjusts the limit
+// based on the latency and the errors of the calls, so the limit doesn't have to be tuned manually.
+// Calls exceeding the limit are rejected with [ErrLimitExceeded].
+//
+// Expose the current limit as metric with e.g.:
+//
+//	limiter := executors.NewAdaptiveLimiter(executors.AdaptiveOptions{})
+//	registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{Name: "concurrency_limit"}, func() float64 {
+//		return float64(limiter.Limit())
+//	}))
+type AdaptiveLimiter struct {
+	// opts are the options of the limiter.
+	opts AdaptiveOptions
+
+	// mu protects the limit and the number of calls in flight.
+	mu sync.Mutex
+	// limit is the current concurrency limit.
+	limit float64
+	// inFlight is the number of running calls.
+	inFlight int
+}
+
+// NewAdaptiveLimiter creates a new [AdaptiveLimiter] with the given options.
+func NewAdaptiveLimiter(opts AdaptiveOptions) *AdaptiveLimiter {
+	if opts.MinLimit < 1 {
+		opts.MinLimit = 1
+	}
+	if opts.MaxLimit < 1 {
+		opts.MaxLimit = 1000
+	}
+	opts.MaxLimit = max(opts.MaxLimit, opts.MinLimit)
+	if opts.InitialLimit < 1 {
+		opts.InitialLimit = 10
+	}
+	opts.InitialLimit = min(max(opts.InitialLimit, opts.MinLimit), opts.MaxLimit)
+	if opts.Algorithm == nil {
+		opts.Algorithm = AIMD(AIMDOptions{})
+	}
+	if opts.IsFailure == nil {
+		opts.IsFailure = DefaultIsFailure
+	}
+	opts.Clock = clockOrReal(opts.Clock)
+	return &AdaptiveLimiter{opts: opts, limit: float64(opts.InitialLimit)}
+}
+
+// Limit returns the current concurrency limit.
+func (a *AdaptiveLimiter) Limit() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return int(a.limit)
+}
+
+// InFlight returns the number of running calls.
+func (a *AdaptiveLimiter) InFlight() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.inFlight
+}
+
+// Wrap returns an effector that runs the effector within the concurrency limit of the limiter.
+// Effectors wrapped by the same limiter share the limit.
+func (a *AdaptiveLimiter) Wrap(effector Effector) Effector {
+	if effector == nil {
+		return noopEffector
+	}
+
+	return func(ctx context.Context) (err error) {
+		inFlight, err := a.acquire()
+		if err != nil {
+			return err
+		}
+
+		start := a.opts.Clock.Now()
+		defer func() {
+			r := recover()
+			if r != nil {
+				// A panic counts as failure and must not leak the slot of the call.
+				err = newPanicError(r, debug.Stack())
+			}
+			a.release(inFlight, start, err)
+			if r != nil {
+				panic(r)
+			}
+		}()
+		return effector(ctx)
+	}
+}
+
+// acquire takes a slot and returns the number of calls in flight including the call itself.
+func (a *AdaptiveLimiter) acquire() (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.inFlight >= int(a.limit) {
+		return 0, ErrLimitExceeded{Limit: int(a.limit)}
+	}
+	a.inFlight++
+	return a.inFlight, nil
+}
+
+// release gives the slot back and adjusts the limit with the outcome of the call.
+func (a *AdaptiveLimiter) release(inFlight int, start time.Time, err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.inFlight--
+
+	// Canceled calls say nothing about the downstream, so they don't affect the limit.
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	o := Outcome{
+		Time:     start,
+		Duration: a.opts.Clock.Now().Sub(start),
+		Failed:   !IsPermanent(err) && a.opts.IsFailure(err),
+	}
+	limit := a.opts.Algorithm.Update(a.limit, inFlight, o)
+	a.limit = min(max(limit, float64(a.opts.MinLimit)), float64(a.opts.MaxLimit))
+}
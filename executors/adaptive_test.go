@@ -0,0 +1,217 @@
+package executors
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAIMD_Update(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     AIMDOptions
+		limit    float64
+		inFlight int
+		outcome  Outcome
+		want     float64
+	}{
+		{
+			name:     "success increases used limit",
+			limit:    10,
+			inFlight: 5,
+			want:     11,
+		},
+		{
+			name:     "success keeps unused limit",
+			limit:    10,
+			inFlight: 1,
+			want:     10,
+		},
+		{
+			name:     "failure decreases limit",
+			limit:    10,
+			inFlight: 5,
+			outcome:  Outcome{Failed: true},
+			want:     9,
+		},
+		{
+			name:     "slow call decreases limit",
+			opts:     AIMDOptions{BackoffRatio: 0.5, LatencyThreshold: time.Second},
+			limit:    10,
+			inFlight: 5,
+			outcome:  Outcome{Duration: 2 * time.Second},
+			want:     5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AIMD(tt.opts).Update(tt.limit, tt.inFlight, tt.outcome); got != tt.want {
+				t.Errorf("AIMD.Update() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGradient_Update(t *testing.T) {
+	tests := []struct {
+		name     string
+		warmup   time.Duration
+		inFlight int
+		outcome  Outcome
+		wantMore bool
+		wantLess bool
+	}{
+		{
+			name:     "steady latency increases limit",
+			warmup:   10 * time.Millisecond,
+			inFlight: 10,
+			outcome:  Outcome{Duration: 10 * time.Millisecond},
+			wantMore: true,
+		},
+		{
+			name:     "latency spike decreases limit",
+			warmup:   10 * time.Millisecond,
+			inFlight: 10,
+			outcome:  Outcome{Duration: time.Second},
+			wantLess: true,
+		},
+		{
+			name:     "failure decreases limit",
+			warmup:   10 * time.Millisecond,
+			inFlight: 10,
+			outcome:  Outcome{Duration: 10 * time.Millisecond, Failed: true},
+			wantLess: true,
+		},
+		{
+			name:     "unused limit is kept",
+			warmup:   10 * time.Millisecond,
+			inFlight: 1,
+			outcome:  Outcome{Duration: 10 * time.Millisecond},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const limit = 16
+			g := Gradient(GradientOptions{})
+			for range 10 {
+				g.Update(limit, limit, Outcome{Duration: tt.warmup})
+			}
+
+			got := g.Update(limit, tt.inFlight, tt.outcome)
+			switch {
+			case tt.wantMore && got <= limit:
+				t.Errorf("Gradient.Update() = %v, want more than %v", got, limit)
+			case tt.wantLess && got >= limit:
+				t.Errorf("Gradient.Update() = %v, want less than %v", got, limit)
+			case !tt.wantMore && !tt.wantLess && got != limit:
+				t.Errorf("Gradient.Update() = %v, want %v", got, limit)
+			}
+		})
+	}
+}
+
+func TestAdaptiveLimiter_Wrap(t *testing.T) {
+	errTest := errors.New("test error")
+	tests := []struct {
+		name      string
+		opts      AdaptiveOptions
+		errs      []error
+		wantLimit int
+	}{
+		{
+			name:      "successes increase the used limit",
+			opts:      AdaptiveOptions{InitialLimit: 1},
+			errs:      []error{nil, nil, nil},
+			wantLimit: 3,
+		},
+		{
+			name:      "failures decrease the limit",
+			opts:      AdaptiveOptions{InitialLimit: 10, Algorithm: AIMD(AIMDOptions{BackoffRatio: 0.5})},
+			errs:      []error{errTest},
+			wantLimit: 5,
+		},
+		{
+			name:      "limit doesn't fall below the minimum",
+			opts:      AdaptiveOptions{InitialLimit: 4, MinLimit: 3, Algorithm: AIMD(AIMDOptions{BackoffRatio: 0.5})},
+			errs:      []error{errTest, errTest},
+			wantLimit: 3,
+		},
+		{
+			name:      "limit doesn't exceed the maximum",
+			opts:      AdaptiveOptions{InitialLimit: 1, MaxLimit: 2},
+			errs:      []error{nil, nil, nil},
+			wantLimit: 2,
+		},
+		{
+			name:      "permanent and canceled errors are ignored",
+			opts:      AdaptiveOptions{InitialLimit: 10},
+			errs:      []error{Permanent(errTest), context.Canceled},
+			wantLimit: 10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := NewAdaptiveLimiter(tt.opts)
+			for _, want := range tt.errs {
+				err := limiter.Wrap(func(context.Context) error { return want }).Do()
+				if !errors.Is(err, want) {
+					t.Errorf("AdaptiveLimiter.Wrap() error = %v, want %v", err, want)
+				}
+			}
+			if got := limiter.Limit(); got != tt.wantLimit {
+				t.Errorf("AdaptiveLimiter.Limit() = %d, want %d", got, tt.wantLimit)
+			}
+		})
+	}
+}
+
+func TestAdaptiveLimiter_Reject(t *testing.T) {
+	limiter := NewAdaptiveLimiter(AdaptiveOptions{InitialLimit: 1})
+	release := make(chan struct{})
+	started := make(chan struct{})
+	done := limiter.Wrap(func(context.Context) error {
+		close(started)
+		<-release
+		return nil
+	}).Go()
+	<-started
+
+	err := limiter.Wrap(noopEffector).Do()
+	var limitErr ErrLimitExceeded
+	if !errors.As(err, &limitErr) || limitErr.Limit != 1 {
+		t.Errorf("AdaptiveLimiter.Wrap() error = %v, want %v", err, ErrLimitExceeded{Limit: 1})
+	}
+	if got := limiter.InFlight(); got != 1 {
+		t.Errorf("AdaptiveLimiter.InFlight() = %d, want 1", got)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Errorf("AdaptiveLimiter.Wrap() error = %v", err)
+	}
+}
+
+func TestAdaptiveLimiter_Panic(t *testing.T) {
+	limiter := NewAdaptiveLimiter(AdaptiveOptions{InitialLimit: 10})
+
+	// A panicking call must give its slot back and count as failure.
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("recovered %v, want %q", r, "boom")
+			}
+		}()
+		_ = limiter.Wrap(func(context.Context) error { panic("boom") }).Do()
+	}()
+
+	if got := limiter.InFlight(); got != 0 {
+		t.Errorf("AdaptiveLimiter.InFlight() = %d, want 0", got)
+	}
+	if got := limiter.Limit(); got >= 10 {
+		t.Errorf("AdaptiveLimiter.Limit() = %d, want less than 10", got)
+	}
+}
@@ -0,0 +1,148 @@
+package executors
+
+import (
+	"math"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// Rand is a source of random numbers used by the jittered backoff strategies.
+// It is satisfied by [rand.Rand], which allows injecting a seeded source for deterministic tests.
+type Rand interface {
+	// Int64N returns a non-negative pseudo-random number in the half-open interval [0,n).
+	Int64N(n int64) int64
+}
+
+// globalRand is a [Rand] that uses the top-level functions of [math/rand/v2].
+type globalRand struct{}
+
+// Int64N returns a non-negative pseudo-random number in the half-open interval [0,n).
+func (globalRand) Int64N(n int64) int64 {
+	return rand.Int64N(n) // #nosec G404 // Jitter doesn't need a cryptographically secure source.
+}
+
+// lockedRand serializes access to a [Rand] that may not be safe for concurrent use.
+type lockedRand struct {
+	mu  sync.Mutex
+	src Rand
+}
+
+// newLockedRand returns a [Rand] that is safe for concurrent use.
+// If the source is nil, the global source of [math/rand/v2] is used.
+func newLockedRand(src Rand) Rand {
+	if src == nil {
+		return globalRand{}
+	}
+	return &lockedRand{src: src}
+}
+
+// Int64N returns a non-negative pseudo-random number in the half-open interval [0,n).
+func (r *lockedRand) Int64N(n int64) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.src.Int64N(n)
+}
+
+// between returns a random duration in the closed interval [low, high].
+func between(rnd Rand, low, high time.Duration) time.Duration {
+	if high <= low {
+		return low
+	}
+	span := int64(high - low)
+	if span == math.MaxInt64 {
+		return low + time.Duration(rnd.Int64N(span))
+	}
+	return low + time.Duration(rnd.Int64N(span+1))
+}
+
+// capOrMax returns the maximum delay or [math.MaxInt64] if the maximum delay is not positive.
+func capOrMax(maxDelay time.Duration) time.Duration {
+	if maxDelay <= 0 {
+		return math.MaxInt64
+	}
+	return maxDelay
+}
+
+// exponential returns base * 2^retries capped at the maximum delay without overflowing.
+func exponential(base, maxDelay time.Duration, retries uint) time.Duration {
+	maxDelay = capOrMax(maxDelay)
+	if base <= 0 {
+		return 0
+	}
+	if retries >= 63 || base > maxDelay>>retries {
+		return maxDelay
+	}
+	return base << retries
+}
+
+// ConstantBackoff returns a [Backoff] that always waits the given delay.
+func ConstantBackoff(delay time.Duration) Backoff {
+	return func(_ uint) time.Duration {
+		return delay
+	}
+}
+
+// LinearBackoff returns a [Backoff] that waits initial + step * retries.
+// The delay is capped at maxDelay. A maxDelay of zero or less disables the cap.
+func LinearBackoff(initial, step, maxDelay time.Duration) Backoff {
+	maxDelay = capOrMax(maxDelay)
+	return func(retries uint) time.Duration {
+		if step > 0 && time.Duration(retries) > (maxDelay-initial)/step {
+			return maxDelay
+		}
+		return min(initial+step*time.Duration(retries), maxDelay)
+	}
+}
+
+// ExponentialBackoff returns a [Backoff] that waits base * 2^retries.
+// The delay is capped at maxDelay. A maxDelay of zero or less disables the cap.
+func ExponentialBackoff(base, maxDelay time.Duration) Backoff {
+	return func(retries uint) time.Duration {
+		return exponential(base, maxDelay, retries)
+	}
+}
+
+// FullJitterBackoff returns a [Backoff] that waits a random duration between zero and the capped exponential delay.
+// If rnd is nil, the global source of [math/rand/v2] is used.
+//
+// See https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/ for a comparison of jitter strategies.
+func FullJitterBackoff(base, maxDelay time.Duration, rnd Rand) Backoff {
+	rnd = newLockedRand(rnd)
+	return func(retries uint) time.Duration {
+		return between(rnd, 0, exponential(base, maxDelay, retries))
+	}
+}
+
+// EqualJitterBackoff returns a [Backoff] that waits half of the capped exponential delay plus a random duration of up to the other half.
+// If rnd is nil, the global source of [math/rand/v2] is used.
+func EqualJitterBackoff(base, maxDelay time.Duration, rnd Rand) Backoff {
+	rnd = newLockedRand(rnd)
+	return func(retries uint) time.Duration {
+		half := exponential(base, maxDelay, retries) / 2
+		return half + between(rnd, 0, half)
+	}
+}
+
+// DecorrelatedJitterBackoff returns a [Backoff] that waits a random duration between base and base * 3^(retries+1),
+// capped at maxDelay. The upper bound is the largest delay the decorrelated jitter of the previous retries can reach,
+// so the delay doesn't depend on earlier calls and the backoff is safe for concurrent use.
+// If rnd is nil, the global source of [math/rand/v2] is used.
+func DecorrelatedJitterBackoff(base, maxDelay time.Duration, rnd Rand) Backoff {
+	rnd = newLockedRand(rnd)
+	maxDelay = capOrMax(maxDelay)
+	return func(retries uint) time.Duration {
+		if base <= 0 {
+			return 0
+		}
+		high := base
+		for range retries + 1 {
+			if high > maxDelay/3 {
+				high = maxDelay
+				break
+			}
+			high *= 3
+		}
+		return min(between(rnd, base, high), maxDelay)
+	}
+}
@@ -0,0 +1,133 @@
+package executors
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+	"time"
+)
+
+// fixedRand is a [Rand] that always returns the same fraction of n.
+type fixedRand struct {
+	// fraction is the fraction of n returned by Int64N, in the range [0, 1).
+	fraction float64
+}
+
+func (r fixedRand) Int64N(n int64) int64 {
+	return int64(float64(n) * r.fraction)
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		name    string
+		backoff Backoff
+		retries []uint
+		want    []time.Duration
+	}{
+		{
+			name:    "constant",
+			backoff: ConstantBackoff(time.Second),
+			retries: []uint{0, 1, 10},
+			want:    []time.Duration{time.Second, time.Second, time.Second},
+		},
+		{
+			name:    "linear",
+			backoff: LinearBackoff(time.Second, 2*time.Second, 0),
+			retries: []uint{0, 1, 10},
+			want:    []time.Duration{time.Second, 3 * time.Second, 21 * time.Second},
+		},
+		{
+			name:    "linear capped",
+			backoff: LinearBackoff(time.Second, 2*time.Second, 5*time.Second),
+			retries: []uint{0, 1, 2, 10, math.MaxUint32},
+			want:    []time.Duration{time.Second, 3 * time.Second, 5 * time.Second, 5 * time.Second, 5 * time.Second},
+		},
+		{
+			name:    "exponential",
+			backoff: ExponentialBackoff(100*time.Millisecond, 0),
+			retries: []uint{0, 1, 4},
+			want:    []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 1600 * time.Millisecond},
+		},
+		{
+			name:    "exponential capped",
+			backoff: ExponentialBackoff(time.Second, 30*time.Second),
+			retries: []uint{0, 4, 5, 10, 64, 200},
+			want:    []time.Duration{time.Second, 16 * time.Second, 30 * time.Second, 30 * time.Second, 30 * time.Second, 30 * time.Second},
+		},
+		{
+			name:    "exponential without cap doesn't overflow",
+			backoff: ExponentialBackoff(time.Second, 0),
+			retries: []uint{100},
+			want:    []time.Duration{math.MaxInt64},
+		},
+		{
+			name:    "full jitter",
+			backoff: FullJitterBackoff(time.Second, 10*time.Second, fixedRand{fraction: 0.5}),
+			retries: []uint{0, 2, 10},
+			want:    []time.Duration{500 * time.Millisecond, 2 * time.Second, 5 * time.Second},
+		},
+		{
+			name:    "equal jitter",
+			backoff: EqualJitterBackoff(time.Second, 10*time.Second, fixedRand{fraction: 0.5}),
+			retries: []uint{0, 2, 10},
+			want:    []time.Duration{750 * time.Millisecond, 3 * time.Second, 7500 * time.Millisecond},
+		},
+		{
+			name:    "decorrelated jitter",
+			backoff: DecorrelatedJitterBackoff(time.Second, 10*time.Second, fixedRand{fraction: 0.5}),
+			retries: []uint{0, 1, 2, 3, 0},
+			want:    []time.Duration{2 * time.Second, 5 * time.Second, 5500 * time.Millisecond, 5500 * time.Millisecond, 2 * time.Second},
+		},
+		{
+			name:    "decorrelated jitter capped",
+			backoff: DecorrelatedJitterBackoff(time.Second, 5*time.Second, fixedRand{fraction: 0.99}),
+			retries: []uint{0, 1, 2},
+			want:    []time.Duration{2980 * time.Millisecond, 4960 * time.Millisecond, 4960 * time.Millisecond},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i, retries := range tt.retries {
+				if got := tt.backoff(retries); got != tt.want[i] {
+					t.Errorf("Backoff(%d) = %v, want %v", retries, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestBackoff_Jitter_Bounds(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(1, 2)) // #nosec G404 // Deterministic source for tests.
+	backoffs := map[string]struct {
+		backoff  Backoff
+		min, max func(retries uint) time.Duration
+	}{
+		"full jitter": {
+			backoff: FullJitterBackoff(time.Second, time.Minute, rnd),
+			min:     func(uint) time.Duration { return 0 },
+			max:     func(r uint) time.Duration { return exponential(time.Second, time.Minute, r) },
+		},
+		"equal jitter": {
+			backoff: EqualJitterBackoff(time.Second, time.Minute, rnd),
+			min:     func(r uint) time.Duration { return exponential(time.Second, time.Minute, r) / 2 },
+			max:     func(r uint) time.Duration { return exponential(time.Second, time.Minute, r) },
+		},
+		"decorrelated jitter": {
+			backoff: DecorrelatedJitterBackoff(time.Second, time.Minute, nil),
+			min:     func(uint) time.Duration { return time.Second },
+			max:     func(uint) time.Duration { return time.Minute },
+		},
+	}
+
+	for name, b := range backoffs {
+		t.Run(name, func(t *testing.T) {
+			for retries := range uint(20) {
+				got := b.backoff(retries)
+				if got < b.min(retries) || got > b.max(retries) {
+					t.Errorf("Backoff(%d) = %v, want between %v and %v", retries, got, b.min(retries), b.max(retries))
+				}
+			}
+		})
+	}
+}
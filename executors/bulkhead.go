@@ -0,0 +1,64 @@
+package executors
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// ErrBulkheadFull is the error returned when a bulkhead has no free slot and its queue is full.
+type ErrBulkheadFull struct{}
+
+// Error returns the error message.
+func (e ErrBulkheadFull) Error() string {
+	return "bulkhead full"
+}
+
+// ErrInvalidBulkhead is the error returned when the maximum number of concurrent calls of a bulkhead is invalid.
+type ErrInvalidBulkhead struct{}
+
+// Error returns the error message.
+func (e ErrInvalidBulkhead) Error() string {
+	return "invalid bulkhead"
+}
+
+// Bulkhead returns an effector that limits the number of concurrent calls of the effector to maxConcurrent.
+// Up to maxQueue additional calls wait for a free slot, all further calls are rejected with [ErrBulkheadFull].
+// A queued call returns the error of the context if the context is done before a slot becomes free.
+// Rejected calls are reported to the observer of the options.
+//
+// Safe to use concurrently.
+func Bulkhead(maxConcurrent, maxQueue int, effector Effector, opts ...Option) Effector {
+	if effector == nil {
+		return noopEffector
+	}
+	if maxConcurrent <= 0 {
+		return func(_ context.Context) error {
+			return ErrInvalidBulkhead{}
+		}
+	}
+
+	o := newOptions(opts)
+	slots := make(chan struct{}, maxConcurrent)
+	var queued atomic.Int64
+	return func(ctx context.Context) error {
+		select {
+		case slots <- struct{}{}:
+		default:
+			if queued.Add(1) > int64(maxQueue) {
+				queued.Add(-1)
+				o.observer.OnRejected(ctx, ErrBulkheadFull{})
+				return ErrBulkheadFull{}
+			}
+			select {
+			case slots <- struct{}{}:
+				queued.Add(-1)
+			case <-ctx.Done():
+				queued.Add(-1)
+				return ctx.Err()
+			}
+		}
+		defer func() { <-slots }()
+
+		return effector(ctx)
+	}
+}
@@ -0,0 +1,134 @@
+package executors
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBulkhead(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxConcurrent int
+		maxQueue      int
+		calls         int
+		wantFull      int
+		wantErr       error
+	}{
+		{
+			name:          "within capacity",
+			maxConcurrent: 2,
+			maxQueue:      0,
+			calls:         2,
+		},
+		{
+			name:          "queued calls",
+			maxConcurrent: 1,
+			maxQueue:      2,
+			calls:         3,
+		},
+		{
+			name:          "rejected calls",
+			maxConcurrent: 2,
+			maxQueue:      1,
+			calls:         5,
+			wantFull:      2,
+		},
+		{
+			name:          "invalid max concurrent",
+			maxConcurrent: 0,
+			calls:         1,
+			wantErr:       ErrInvalidBulkhead{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release := make(chan struct{})
+			var running, peak atomic.Int32
+			effector := Effector(func(ctx context.Context) error {
+				n := running.Add(1)
+				defer running.Add(-1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				<-release
+				return nil
+			}).WithBulkhead(tt.maxConcurrent, tt.maxQueue)
+
+			var wg sync.WaitGroup
+			errs := make(chan error, tt.calls)
+			for range tt.calls {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					errs <- effector.Do()
+				}()
+			}
+
+			// Give all calls time to either enter, queue up or be rejected.
+			time.Sleep(20 * time.Millisecond)
+			close(release)
+			wg.Wait()
+			close(errs)
+
+			full := 0
+			for err := range errs {
+				switch {
+				case tt.wantErr != nil:
+					if !errors.Is(err, tt.wantErr) {
+						t.Errorf("Bulkhead() error = %v, want %v", err, tt.wantErr)
+					}
+				case errors.Is(err, ErrBulkheadFull{}):
+					full++
+				case err != nil:
+					t.Errorf("Bulkhead() unexpected error = %v", err)
+				}
+			}
+
+			if full != tt.wantFull {
+				t.Errorf("Bulkhead() rejected %d calls, want %d", full, tt.wantFull)
+			}
+			if tt.wantErr == nil && int(peak.Load()) > tt.maxConcurrent {
+				t.Errorf("Bulkhead() ran %d calls concurrently, want at most %d", peak.Load(), tt.maxConcurrent)
+			}
+		})
+	}
+}
+
+func TestBulkhead_Context_Cancel(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	effector := Bulkhead(1, 1, func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+
+	go func() { _ = effector.Do() }()
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := effector.Do(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Bulkhead() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	// The canceled call must have left the queue, so the next call may queue up again.
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := effector.Do(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Bulkhead() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestBulkhead_NilEffector(t *testing.T) {
+	if err := Bulkhead(1, 0, nil).Do(); err != nil {
+		t.Errorf("Bulkhead() error = %v, want nil", err)
+	}
+}
@@ -2,6 +2,8 @@ package executors
 
 import (
 	"context"
+	"runtime/debug"
+	"sync"
 	"time"
 )
 
@@ -13,32 +15,257 @@ func (e ErrCircuitOpen) Error() string {
 	return "circuit open"
 }
 
-// CircuitBreaker returns an effector that stops calling the task if it fails a certain number of times, until a certain amount of time has passed.
-func CircuitBreaker(maxFailures int, resetTimeout time.Duration, effector Effector) Effector {
+// State is the state of a circuit breaker.
+type State int
+
+const (
+	// StateClosed is the state in which all calls are passed through to the effector.
+	StateClosed State = iota
+	// StateOpen is the state in which all calls are rejected with [ErrCircuitOpen].
+	StateOpen
+	// StateHalfOpen is the state in which a limited number of trial calls is passed through
+	// to decide whether the circuit should be closed again.
+	StateHalfOpen
+)
+
+// String returns the name of the state.
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// BreakerOptions configures a [Breaker].
+type BreakerOptions struct {
+	// MaxFailures is the number of consecutive failures after which the circuit opens.
+	// It is only used if no TripPolicy is set. Defaults to 1.
+	MaxFailures int
+	// TripPolicy decides when the closed circuit opens, e.g. [CountWindow] or [TimeWindow].
+	// Defaults to [ConsecutiveFailures] with MaxFailures.
+	TripPolicy TripPolicy
+	// ResetTimeout is the amount of time the circuit stays open before it transitions to half-open.
+	ResetTimeout time.Duration
+	// HalfOpenMaxCalls is the maximum number of trial calls that are allowed while the circuit is half-open.
+	// Defaults to 1.
+	HalfOpenMaxCalls int
+	// SuccessThreshold is the number of successful trial calls required to close the circuit again.
+	// It is capped at HalfOpenMaxCalls. Defaults to 1.
+	SuccessThreshold int
+	// IsFailure reports whether an error counts as a failure. Defaults to [DefaultIsFailure].
+	// Errors marked with [Permanent] are never recorded.
+	IsFailure func(error) bool
+	// Observer is notified whenever the circuit changes its state.
+	Observer Observer
+	// Clock measures the reset timeout and the duration of the calls. Defaults to [RealClock].
+	Clock Clock
+}
+
+// Breaker is a circuit breaker with a closed, open and half-open state.
+//
+// While closed, all calls are passed through and their outcomes are recorded by the [TripPolicy].
+// Once the policy trips, the circuit opens and all calls are rejected with [ErrCircuitOpen] until ResetTimeout has passed.
+// The circuit then becomes half-open and lets up to HalfOpenMaxCalls trial calls through.
+// A failing trial call opens the circuit again, while SuccessThreshold successful trial calls close it.
+//
+// Safe to use concurrently.
+type Breaker struct {
+	// opts are the options of the breaker.
+	opts BreakerOptions
+
+	// mu protects the fields below.
+	mu sync.Mutex
+	// state is the current state of the circuit.
+	state State
+	// generation is incremented on every state change to discard results of calls started in a previous state.
+	generation uint64
+	// openedAt is the time the circuit was opened.
+	openedAt time.Time
+	// trials is the number of trial calls admitted while half-open.
+	trials int
+	// successes is the number of successful trial calls while half-open.
+	successes int
+	// transitions are the state changes that have not been reported to the observer yet.
+	transitions []transition
+}
+
+// transition is a change of the state of a circuit.
+type transition struct {
+	from, to State
+}
+
+// NewBreaker creates a new [Breaker] with the given options.
+func NewBreaker(opts BreakerOptions) *Breaker {
+	if opts.MaxFailures < 1 {
+		opts.MaxFailures = 1
+	}
+	if opts.TripPolicy == nil {
+		opts.TripPolicy = ConsecutiveFailures(opts.MaxFailures)
+	}
+	if opts.HalfOpenMaxCalls < 1 {
+		opts.HalfOpenMaxCalls = 1
+	}
+	if opts.SuccessThreshold < 1 {
+		opts.SuccessThreshold = 1
+	}
+	opts.SuccessThreshold = min(opts.SuccessThreshold, opts.HalfOpenMaxCalls)
+	if opts.IsFailure == nil {
+		opts.IsFailure = DefaultIsFailure
+	}
+	opts.Observer = observerOrNoop(opts.Observer)
+	opts.Clock = clockOrReal(opts.Clock)
+
+	return &Breaker{opts: opts}
+}
+
+// State returns the current state of the circuit.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.unlock(context.Background())
+	b.refresh(b.opts.Clock.Now())
+	return b.state
+}
+
+// Wrap returns an effector that runs the effector through the circuit breaker.
+// All effectors wrapped by the same breaker share its state.
+func (b *Breaker) Wrap(effector Effector) Effector {
 	if effector == nil {
 		return noopEffector
 	}
 
-	// failures is the number of failures that have occurred.
-	var failures int
-	// lastFailure is the time of the last failure.
-	var lastFailure time.Time
-
-	return func(ctx context.Context) error {
-		if failures >= maxFailures && time.Since(lastFailure) < resetTimeout {
-			return ErrCircuitOpen{}
+	return func(ctx context.Context) (err error) {
+		generation, err := b.acquire(ctx)
+		if err != nil {
+			return err
 		}
 
-		if err := effector(ctx); err != nil {
-			failures++
-			lastFailure = time.Now()
-			if failures >= maxFailures {
-				return ErrCircuitOpen{}
+		start := b.opts.Clock.Now()
+		defer func() {
+			r := recover()
+			if r != nil {
+				// A panic counts as failure, so a panicking trial call doesn't block the half-open circuit forever.
+				err = newPanicError(r, debug.Stack())
 			}
-			return err
+			opened := b.release(ctx, generation, start, err)
+			if r != nil {
+				panic(r)
+			}
+			if opened {
+				err = ErrCircuitOpen{}
+			}
+		}()
+		return effector(ctx)
+	}
+}
+
+// acquire checks whether a call is permitted and returns the generation it belongs to.
+func (b *Breaker) acquire(ctx context.Context) (uint64, error) {
+	b.mu.Lock()
+	defer b.unlock(ctx)
+
+	b.refresh(b.opts.Clock.Now())
+	switch b.state {
+	case StateOpen:
+		return 0, ErrCircuitOpen{}
+	case StateHalfOpen:
+		if b.trials >= b.opts.HalfOpenMaxCalls {
+			return 0, ErrCircuitOpen{}
+		}
+		b.trials++
+	}
+	return b.generation, nil
+}
+
+// release records the result of a call that was permitted in the given generation and started at the given time.
+// Returns true if the call caused the circuit to open.
+func (b *Breaker) release(ctx context.Context, generation uint64, start time.Time, err error) bool {
+	b.mu.Lock()
+	defer b.unlock(ctx)
+
+	now := b.opts.Clock.Now()
+	b.refresh(now)
+	if generation != b.generation {
+		return false
+	}
+
+	if IsPermanent(err) {
+		// Permanent errors say nothing about the health of the dependency,
+		// so the call is neither recorded nor does it use up a trial call.
+		if b.state == StateHalfOpen {
+			b.trials--
 		}
+		return false
+	}
 
-		failures = 0
-		return nil
+	failed := b.opts.IsFailure(err)
+	switch b.state {
+	case StateClosed:
+		if b.opts.TripPolicy.Record(Outcome{Time: now, Duration: now.Sub(start), Failed: failed}) {
+			b.setState(StateOpen, now)
+			return true
+		}
+	case StateHalfOpen:
+		if failed {
+			b.setState(StateOpen, now)
+			return true
+		}
+		b.successes++
+		if b.successes >= b.opts.SuccessThreshold {
+			b.setState(StateClosed, now)
+		}
+	}
+	return false
+}
+
+// refresh transitions an open circuit to half-open once the reset timeout has passed.
+// Must be called with the lock held.
+func (b *Breaker) refresh(now time.Time) {
+	if b.state == StateOpen && now.Sub(b.openedAt) >= b.opts.ResetTimeout {
+		b.setState(StateHalfOpen, now)
+	}
+}
+
+// setState changes the state of the circuit and resets all counters.
+// Must be called with the lock held.
+func (b *Breaker) setState(state State, now time.Time) {
+	b.transitions = append(b.transitions, transition{from: b.state, to: state})
+	b.state = state
+	b.generation++
+	b.trials = 0
+	b.successes = 0
+	switch state {
+	case StateOpen:
+		b.openedAt = now
+	case StateClosed:
+		b.opts.TripPolicy.Reset()
+	}
+}
+
+// unlock releases the lock and reports all pending state changes to the observer.
+// The observer is called without the lock held, so it may safely call back into the breaker.
+func (b *Breaker) unlock(ctx context.Context) {
+	transitions := b.transitions
+	b.transitions = nil
+	b.mu.Unlock()
+
+	for _, t := range transitions {
+		b.opts.Observer.OnStateChange(ctx, t.from, t.to)
+	}
+}
+
+// CircuitBreaker returns an effector that stops calling the task if it fails a certain number of times, until a certain amount of time has passed.
+// After the reset timeout a single trial call is let through to decide whether the circuit closes again.
+//
+// Use [NewBreaker] to configure the tripping policy and the half-open behavior or to inspect the state of the circuit.
+func CircuitBreaker(maxFailures int, resetTimeout time.Duration, effector Effector) Effector {
+	if effector == nil {
+		return noopEffector
 	}
+	return NewBreaker(BreakerOptions{MaxFailures: maxFailures, ResetTimeout: resetTimeout}).Wrap(effector)
 }
@@ -3,6 +3,8 @@ package executors
 import (
 	"context"
 	"errors"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -65,3 +67,252 @@ func TestCircuitBreaker(t *testing.T) {
 		})
 	}
 }
+
+func TestBreaker_State(t *testing.T) {
+	errFailed := errors.New("failed")
+	tests := []struct {
+		name             string
+		opts             BreakerOptions
+		calls            []error
+		wait             time.Duration
+		trials           []error
+		want             State
+		wantTrialsDenied int
+	}{
+		{
+			name:  "closed without failures",
+			opts:  BreakerOptions{MaxFailures: 2, ResetTimeout: time.Hour},
+			calls: []error{nil, nil},
+			want:  StateClosed,
+		},
+		{
+			name:  "closed below max failures",
+			opts:  BreakerOptions{MaxFailures: 2, ResetTimeout: time.Hour},
+			calls: []error{errFailed, nil, errFailed},
+			want:  StateClosed,
+		},
+		{
+			name:  "open after max failures",
+			opts:  BreakerOptions{MaxFailures: 2, ResetTimeout: time.Hour},
+			calls: []error{errFailed, errFailed},
+			want:  StateOpen,
+		},
+		{
+			name:  "half-open after reset timeout",
+			opts:  BreakerOptions{MaxFailures: 1, ResetTimeout: 10 * time.Millisecond},
+			calls: []error{errFailed},
+			wait:  20 * time.Millisecond,
+			want:  StateHalfOpen,
+		},
+		{
+			name:   "closed after successful trial",
+			opts:   BreakerOptions{MaxFailures: 1, ResetTimeout: 10 * time.Millisecond},
+			calls:  []error{errFailed},
+			wait:   20 * time.Millisecond,
+			trials: []error{nil},
+			want:   StateClosed,
+		},
+		{
+			name:   "open after failed trial",
+			opts:   BreakerOptions{MaxFailures: 1, ResetTimeout: 10 * time.Millisecond},
+			calls:  []error{errFailed},
+			wait:   20 * time.Millisecond,
+			trials: []error{errFailed},
+			want:   StateOpen,
+		},
+		{
+			name:   "half-open until success threshold is reached",
+			opts:   BreakerOptions{MaxFailures: 1, ResetTimeout: 10 * time.Millisecond, HalfOpenMaxCalls: 3, SuccessThreshold: 3},
+			calls:  []error{errFailed},
+			wait:   20 * time.Millisecond,
+			trials: []error{nil, nil},
+			want:   StateHalfOpen,
+		},
+		{
+			name:             "trial calls beyond the limit are rejected",
+			opts:             BreakerOptions{MaxFailures: 1, ResetTimeout: 10 * time.Millisecond, HalfOpenMaxCalls: 2, SuccessThreshold: 2},
+			calls:            []error{errFailed},
+			wait:             20 * time.Millisecond,
+			trials:           []error{nil, nil, nil},
+			want:             StateClosed,
+			wantTrialsDenied: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBreaker(tt.opts)
+			for _, want := range tt.calls {
+				_ = b.Wrap(func(ctx context.Context) error { return want }).Do()
+			}
+			time.Sleep(tt.wait)
+
+			// Start all trial calls before any of them finishes to exercise the half-open limit.
+			release := make(chan struct{})
+			var denied atomic.Int32
+			var wg sync.WaitGroup
+			for _, want := range tt.trials {
+				wg.Add(1)
+				started := make(chan struct{})
+				go func() {
+					defer wg.Done()
+					err := b.Wrap(func(ctx context.Context) error {
+						close(started)
+						<-release
+						return want
+					}).Do()
+					if errors.Is(err, ErrCircuitOpen{}) && want == nil {
+						denied.Add(1)
+						close(started)
+					}
+				}()
+				<-started
+			}
+			close(release)
+			wg.Wait()
+
+			if got := b.State(); got != tt.want {
+				t.Errorf("Breaker.State() = %v, want %v", got, tt.want)
+			}
+			if got := int(denied.Load()); got != tt.wantTrialsDenied {
+				t.Errorf("Breaker denied %d trial calls, want %d", got, tt.wantTrialsDenied)
+			}
+		})
+	}
+}
+
+func TestBreaker_Concurrent(t *testing.T) {
+	b := NewBreaker(BreakerOptions{MaxFailures: 5, ResetTimeout: time.Millisecond, HalfOpenMaxCalls: 2})
+	var calls atomic.Int32
+	effector := b.Wrap(func(ctx context.Context) error {
+		if calls.Add(1)%3 == 0 {
+			return errors.New("failed")
+		}
+		return nil
+	})
+
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 20 {
+				_ = effector.Do()
+				_ = b.State()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestState_String(t *testing.T) {
+	tests := []struct {
+		state State
+		want  string
+	}{
+		{state: StateClosed, want: "closed"},
+		{state: StateOpen, want: "open"},
+		{state: StateHalfOpen, want: "half-open"},
+		{state: State(-1), want: "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.state.String(); got != tt.want {
+				t.Errorf("State.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBreaker_Classification(t *testing.T) {
+	errIgnored := errors.New("ignored")
+	tests := []struct {
+		name string
+		opts BreakerOptions
+		err  error
+		want State
+	}{
+		{
+			name: "failures are counted",
+			opts: BreakerOptions{MaxFailures: 2, ResetTimeout: time.Hour},
+			err:  errors.New("failed"),
+			want: StateOpen,
+		},
+		{
+			name: "permanent errors are not counted",
+			opts: BreakerOptions{MaxFailures: 2, ResetTimeout: time.Hour},
+			err:  Permanent(errors.New("invalid")),
+			want: StateClosed,
+		},
+		{
+			name: "custom classifier",
+			opts: BreakerOptions{MaxFailures: 2, ResetTimeout: time.Hour, IsFailure: func(err error) bool {
+				return err != nil && !errors.Is(err, errIgnored)
+			}},
+			err:  errIgnored,
+			want: StateClosed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBreaker(tt.opts)
+			for range 3 {
+				_ = b.Wrap(func(ctx context.Context) error { return tt.err }).Do()
+			}
+			if got := b.State(); got != tt.want {
+				t.Errorf("Breaker.State() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBreaker_HalfOpen_Permanent(t *testing.T) {
+	b := NewBreaker(BreakerOptions{MaxFailures: 1, ResetTimeout: 10 * time.Millisecond})
+	_ = b.Wrap(func(ctx context.Context) error { return errors.New("failed") }).Do()
+	time.Sleep(20 * time.Millisecond)
+
+	// A permanent error must not use up the only trial call.
+	err := b.Wrap(func(ctx context.Context) error { return Permanent(errors.New("invalid")) }).Do()
+	if !IsPermanent(err) {
+		t.Fatalf("Breaker.Wrap() error = %v, want permanent error", err)
+	}
+	if got := b.State(); got != StateHalfOpen {
+		t.Fatalf("Breaker.State() = %v, want %v", got, StateHalfOpen)
+	}
+
+	if err := b.Wrap(noopEffector).Do(); err != nil {
+		t.Fatalf("Breaker.Wrap() error = %v, want nil", err)
+	}
+	if got := b.State(); got != StateClosed {
+		t.Errorf("Breaker.State() = %v, want %v", got, StateClosed)
+	}
+}
+
+func TestBreaker_HalfOpen_Panic(t *testing.T) {
+	b := NewBreaker(BreakerOptions{MaxFailures: 1, ResetTimeout: 10 * time.Millisecond})
+	_ = b.Wrap(func(ctx context.Context) error { return errors.New("failed") }).Do()
+	time.Sleep(20 * time.Millisecond)
+
+	// A panicking trial call must be recorded as failure and must not keep its trial slot.
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("recovered %v, want %q", r, "boom")
+			}
+		}()
+		_ = b.Wrap(func(ctx context.Context) error { panic("boom") }).Do()
+	}()
+	if got := b.State(); got != StateOpen {
+		t.Fatalf("Breaker.State() = %v, want %v", got, StateOpen)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if err := b.Wrap(noopEffector).Do(); err != nil {
+		t.Fatalf("Breaker.Wrap() error = %v, want nil", err)
+	}
+	if got := b.State(); got != StateClosed {
+		t.Errorf("Breaker.State() = %v, want %v", got, StateClosed)
+	}
+}
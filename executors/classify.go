@@ -0,0 +1,90 @@
+package executors
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrPermanent wraps an error that will never succeed when retried.
+// Permanent errors are not retried by a [Retrier] and are not counted by a [Breaker].
+type ErrPermanent struct {
+	Err error
+}
+
+// Error returns the error message of the wrapped error.
+func (e *ErrPermanent) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *ErrPermanent) Unwrap() error { return e.Err }
+
+// ErrRetryable wraps an error that should be retried even if it would not be retried otherwise.
+type ErrRetryable struct {
+	Err error
+}
+
+// Error returns the error message of the wrapped error.
+func (e *ErrRetryable) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *ErrRetryable) Unwrap() error { return e.Err }
+
+// Permanent marks the error as permanent. Returns nil if the error is nil.
+//
+// Example:
+//
+//	effector := func(ctx context.Context) error {
+//		if err := validate(input); err != nil {
+//			return executors.Permanent(err)
+//		}
+//		return call(ctx, input)
+//	}
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ErrPermanent{Err: err}
+}
+
+// Retryable marks the error as retryable. Returns nil if the error is nil.
+func Retryable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ErrRetryable{Err: err}
+}
+
+// IsPermanent reports whether any error in the error's tree has been marked with [Permanent].
+func IsPermanent(err error) bool {
+	var target *ErrPermanent
+	return errors.As(err, &target)
+}
+
+// IsRetryable reports whether any error in the error's tree has been marked with [Retryable].
+func IsRetryable(err error) bool {
+	var target *ErrRetryable
+	return errors.As(err, &target)
+}
+
+// DefaultRetryIf is the default classifier of a [Retrier].
+// It reports whether the error should be retried, which is the case for all errors
+// except those marked with [Permanent] and [context.Canceled], unless they are marked with [Retryable].
+func DefaultRetryIf(err error) bool {
+	switch {
+	case err == nil, IsPermanent(err):
+		return false
+	case IsRetryable(err):
+		return true
+	default:
+		return !errors.Is(err, context.Canceled)
+	}
+}
+
+// DefaultIsFailure is the default classifier of a [Breaker].
+// It reports whether the error counts as a failure, which is the case for all non-nil errors.
+func DefaultIsFailure(err error) bool {
+	return err != nil
+}
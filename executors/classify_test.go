@@ -0,0 +1,62 @@
+package executors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestDefaultRetryIf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil error", err: nil, want: false},
+		{name: "plain error", err: errors.New("failed"), want: true},
+		{name: "deadline exceeded", err: context.DeadlineExceeded, want: true},
+		{name: "context canceled", err: context.Canceled, want: false},
+		{name: "permanent error", err: Permanent(errors.New("invalid")), want: false},
+		{name: "wrapped permanent error", err: fmt.Errorf("request: %w", Permanent(errors.New("invalid"))), want: false},
+		{name: "retryable context canceled", err: Retryable(context.Canceled), want: true},
+		{name: "permanent retryable error", err: Permanent(Retryable(errors.New("failed"))), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DefaultRetryIf(tt.err); got != tt.want {
+				t.Errorf("DefaultRetryIf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPermanent(t *testing.T) {
+	if Permanent(nil) != nil {
+		t.Errorf("Permanent(nil) != nil")
+	}
+	if Retryable(nil) != nil {
+		t.Errorf("Retryable(nil) != nil")
+	}
+
+	wrapped := errors.New("invalid")
+	err := Permanent(wrapped)
+	if !errors.Is(err, wrapped) {
+		t.Errorf("errors.Is(Permanent(err), err) = false, want true")
+	}
+	if err.Error() != wrapped.Error() {
+		t.Errorf("Permanent(err).Error() = %q, want %q", err.Error(), wrapped.Error())
+	}
+	if !IsPermanent(err) || IsRetryable(err) {
+		t.Errorf("IsPermanent() = %v, IsRetryable() = %v, want true, false", IsPermanent(err), IsRetryable(err))
+	}
+
+	err = Retryable(wrapped)
+	if !errors.Is(err, wrapped) {
+		t.Errorf("errors.Is(Retryable(err), err) = false, want true")
+	}
+	if !IsRetryable(err) || IsPermanent(err) {
+		t.Errorf("IsPermanent() = %v, IsRetryable() = %v, want false, true", IsPermanent(err), IsRetryable(err))
+	}
+}
@@ -0,0 +1,152 @@
+package executors
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Clock is the source of time of the policies.
+// It can be replaced with a fake clock, e.g. the one of the executorstest package, to test policies without waiting.
+//
+// Implementations must be safe for concurrent use.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// NewTimer creates a new [Timer] that sends the current time on its channel after the duration.
+	NewTimer(d time.Duration) Timer
+	// AfterFunc calls f in its own goroutine after the duration.
+	// The channel of the returned [Timer] is nil.
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer is a timer created by a [Clock].
+type Timer interface {
+	// C returns the channel on which the time is delivered.
+	C() <-chan time.Time
+	// Stop prevents the timer from firing.
+	// Returns false if the timer has already fired or been stopped.
+	Stop() bool
+	// Reset changes the timer to fire after the duration.
+	// Returns false if the timer had already fired or been stopped.
+	Reset(d time.Duration) bool
+}
+
+// RealClock returns the [Clock] of the system. It is the default of all policies.
+func RealClock() Clock {
+	return realClock{}
+}
+
+// realClock is the [Clock] returned by [RealClock].
+type realClock struct{}
+
+// Now returns the current time.
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// NewTimer creates a new timer that fires after the duration.
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+// AfterFunc calls f in its own goroutine after the duration.
+func (realClock) AfterFunc(d time.Duration, f func()) Timer {
+	return realTimer{time.AfterFunc(d, f)}
+}
+
+// realTimer is a [Timer] backed by a [time.Timer].
+type realTimer struct {
+	*time.Timer
+}
+
+// C returns the channel on which the time is delivered.
+func (t realTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+// WithClock is an [Option] that sets the [Clock] of a policy. Defaults to [RealClock].
+func WithClock(c Clock) Option {
+	return func(opts *options) {
+		opts.clock = clockOrReal(c)
+	}
+}
+
+// clockOrReal returns the clock or the [RealClock] if it is nil.
+func clockOrReal(c Clock) Clock {
+	if c == nil {
+		return realClock{}
+	}
+	return c
+}
+
+// sleep waits for the duration on the clock and returns the context error if the context is done first.
+func sleep(ctx context.Context, clock Clock, d time.Duration) error {
+	timer := clock.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// withTimeoutCause is like [context.WithTimeoutCause], but measures the timeout with the clock.
+// Contexts of other clocks than the [RealClock] don't report a deadline.
+func withTimeoutCause(ctx context.Context, clock Clock, timeout time.Duration, cause error) (context.Context, context.CancelFunc) {
+	if _, ok := clock.(realClock); ok {
+		return context.WithTimeoutCause(ctx, timeout, cause)
+	}
+
+	cctx, cancel := context.WithCancelCause(ctx)
+	tctx := &timeoutContext{Context: cctx, done: make(chan struct{})}
+	context.AfterFunc(cctx, tctx.close)
+	timer := clock.AfterFunc(timeout, func() {
+		tctx.timedOut.Store(true)
+		cancel(cause)
+		tctx.close()
+	})
+	return tctx, func() {
+		timer.Stop()
+		cancel(context.Canceled)
+		tctx.close()
+	}
+}
+
+// timeoutContext is a context canceled by the timer of a [Clock].
+// It has its own done channel, so contexts derived from it inherit its error instead of the one of the wrapped context.
+type timeoutContext struct {
+	context.Context
+	// done is closed once the wrapped context is done.
+	done chan struct{}
+	// closeOnce closes done once.
+	closeOnce sync.Once
+	// timedOut is set before the context is canceled by the timer.
+	timedOut atomic.Bool
+}
+
+// close closes the done channel.
+func (c *timeoutContext) close() {
+	c.closeOnce.Do(func() { close(c.done) })
+}
+
+// Done returns a channel that is closed once the context is done.
+func (c *timeoutContext) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err returns [context.DeadlineExceeded] if the context was canceled by its timer, like a context with a deadline.
+func (c *timeoutContext) Err() error {
+	select {
+	case <-c.done:
+	default:
+		return nil
+	}
+	if c.timedOut.Load() && errors.Is(c.Context.Err(), context.Canceled) {
+		return context.DeadlineExceeded
+	}
+	return c.Context.Err()
+}
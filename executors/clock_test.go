@@ -0,0 +1,92 @@
+package executors
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// manualClock is a [Clock] whose timers only fire when fire is called.
+type manualClock struct {
+	realClock
+	// fire is the function of the last timer created with AfterFunc.
+	fire func()
+}
+
+// AfterFunc records the function instead of scheduling it.
+func (c *manualClock) AfterFunc(_ time.Duration, f func()) Timer {
+	c.fire = f
+	return realTimer{time.NewTimer(time.Hour)}
+}
+
+func TestSleep(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		delay   time.Duration
+		cancel  bool
+		wantErr error
+	}{
+		{name: "elapsed", delay: time.Millisecond},
+		{name: "zero delay", delay: 0},
+		{name: "canceled", delay: time.Hour, cancel: true, wantErr: context.Canceled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancel {
+				cancel()
+			}
+
+			if err := sleep(ctx, RealClock(), tt.delay); !errors.Is(err, tt.wantErr) {
+				t.Errorf("sleep() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWithTimeoutCause(t *testing.T) {
+	t.Parallel()
+
+	cause := errors.New("cause")
+	t.Run("real clock", func(t *testing.T) {
+		ctx, cancel := withTimeoutCause(context.Background(), RealClock(), time.Millisecond, cause)
+		defer cancel()
+		<-ctx.Done()
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) || !errors.Is(context.Cause(ctx), cause) {
+			t.Errorf("withTimeoutCause() err = %v, cause = %v", ctx.Err(), context.Cause(ctx))
+		}
+	})
+
+	t.Run("other clock", func(t *testing.T) {
+		clock := &manualClock{}
+		ctx, cancel := withTimeoutCause(context.Background(), clock, time.Millisecond, cause)
+		defer cancel()
+		if _, ok := ctx.Deadline(); ok {
+			t.Error("withTimeoutCause() reports a deadline")
+		}
+
+		child, cancelChild := context.WithCancel(ctx)
+		defer cancelChild()
+		clock.fire()
+		<-child.Done()
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) || !errors.Is(context.Cause(ctx), cause) {
+			t.Errorf("withTimeoutCause() err = %v, cause = %v", ctx.Err(), context.Cause(ctx))
+		}
+		if !errors.Is(child.Err(), context.DeadlineExceeded) {
+			t.Errorf("child err = %v, want %v", child.Err(), context.DeadlineExceeded)
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := withTimeoutCause(context.Background(), &manualClock{}, time.Hour, cause)
+		cancel()
+		if !errors.Is(ctx.Err(), context.Canceled) {
+			t.Errorf("withTimeoutCause() err = %v, want %v", ctx.Err(), context.Canceled)
+		}
+	})
+}
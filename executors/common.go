@@ -5,6 +5,7 @@ package executors
 import (
 	"context"
 	"errors"
+	"sync"
 	"time"
 
 	"golang.org/x/sync/errgroup"
@@ -28,10 +29,11 @@ func (e Effector) Do(ctx ...context.Context) error {
 
 // Go runs the effector concurrently and returns a channel that will receive the error.
 // The channel is closed when the effector finishes.
+// A panic of the effector is recovered and received as [*PanicError].
 func (e Effector) Go(ctx ...context.Context) <-chan error {
 	ch := make(chan error, 1)
 	go func() {
-		ch <- e.Do(ctx...)
+		ch <- Protector(e).Do(ctx...)
 		close(ch)
 	}()
 	return ch
@@ -43,13 +45,23 @@ func (e Effector) WithRetry(retrier Retrier) Effector {
 }
 
 // WithTimeout returns an effector that runs the effector with a timeout.
-func (e Effector) WithTimeout(timeout time.Duration) Effector {
-	return Timeouter(timeout, e)
+func (e Effector) WithTimeout(timeout time.Duration, opts ...Option) Effector {
+	return Timeouter(timeout, e, opts...)
 }
 
 // WithRateLimit returns an effector that runs the effector with the specified rate limit.
-func (e Effector) WithRateLimit(r rate.Limit) Effector {
-	return RateLimiter(r, e)
+func (e Effector) WithRateLimit(r rate.Limit, opts ...Option) Effector {
+	return RateLimiter(r, e, opts...)
+}
+
+// WithLimiter returns an effector that runs the effector once the given [Limiter] permits it.
+func (e Effector) WithLimiter(l Limiter, opts ...Option) Effector {
+	return Limited(l, e, opts...)
+}
+
+// WithKeyedRateLimit returns an effector that runs the effector with the specified rate limit per key.
+func (e Effector) WithKeyedRateLimit(keyFunc KeyFunc, r rate.Limit, opts ...Option) Effector {
+	return KeyedRateLimiter(keyFunc, r, e, opts...)
 }
 
 // WithCircuitBreaker returns an effector that stops calling the task if it fails a certain number of times, until a certain amount of time has passed.
@@ -57,9 +69,46 @@ func (e Effector) WithCircuitBreaker(maxFailures int, resetTimeout time.Duration
 	return CircuitBreaker(maxFailures, resetTimeout, e)
 }
 
+// WithKeyedCircuitBreaker returns an effector that runs the effector with a circuit breaker per key.
+func (e Effector) WithKeyedCircuitBreaker(keyFunc KeyFunc, maxFailures int, resetTimeout time.Duration, opts ...Option) Effector {
+	return KeyedCircuitBreaker(keyFunc, maxFailures, resetTimeout, e, opts...)
+}
+
+// WithBreaker returns an effector that runs the effector through the given [Breaker].
+func (e Effector) WithBreaker(b *Breaker) Effector {
+	return b.Wrap(e)
+}
+
+// WithBulkhead returns an effector that limits the number of concurrent calls of the effector.
+// Up to maxQueue calls wait for a free slot, all further calls are rejected with [ErrBulkheadFull].
+func (e Effector) WithBulkhead(maxConcurrent, maxQueue int, opts ...Option) Effector {
+	return Bulkhead(maxConcurrent, maxQueue, e, opts...)
+}
+
+// WithAdaptiveLimit returns an effector that runs the effector within the adaptive concurrency limit of the given [AdaptiveLimiter].
+func (e Effector) WithAdaptiveLimit(a *AdaptiveLimiter) Effector {
+	return a.Wrap(e)
+}
+
+// WithHedge returns an effector that starts up to maxHedges duplicate calls of the effector
+// if no call has succeeded after the given delay and returns the first success.
+func (e Effector) WithHedge(delay time.Duration, maxHedges int, opts ...Option) Effector {
+	return Hedge(delay, maxHedges, e, opts...)
+}
+
+// WithPolicy returns an effector that runs the effector with all policies configured by the given [Policy].
+func (e Effector) WithPolicy(p *Policy, opts ...Option) Effector {
+	return p.Apply(e, opts...)
+}
+
+// WithSingleFlight returns an effector that collapses concurrent calls with the same key into one execution.
+func (e Effector) WithSingleFlight(keyFunc KeyFunc) Effector {
+	return SingleFlight(keyFunc, e)
+}
+
 // WithProtection returns an effector that recovers from panics and returns them as errors.
-func (e Effector) WithProtection() Effector {
-	return Protector(e)
+func (e Effector) WithProtection(opts ...Option) Effector {
+	return Protector(e, opts...)
 }
 
 // WithFallback returns an effector that runs the fallback effector if the first one returns an error.
@@ -73,8 +122,14 @@ func (e Effector) WithFallback(fallback Effector) Effector {
 	}
 }
 
+// Schedule returns a [Scheduler] that runs the effector on the given schedule.
+func (e Effector) Schedule(schedule Schedule, opts SchedulerOptions) *Scheduler {
+	return NewScheduler(schedule, e, opts)
+}
+
 // Concurrent returns an effector that runs the effectors concurrently and
 // returns all errors that occurred as wrapped [errors.Join] error.
+// Panics of the effectors are recovered and returned as [*PanicError] in the joined error.
 //
 // Safe to use concurrently.
 func Concurrent(effectors ...Effector) Effector {
@@ -83,7 +138,7 @@ func Concurrent(effectors ...Effector) Effector {
 		errs := make(chan error, len(effectors))
 		for _, effector := range effectors {
 			g.Go(func() error {
-				err := effector.Do(ctx)
+				err := Protector(effector).Do(ctx)
 				errs <- err
 				return err
 			})
@@ -103,6 +158,37 @@ func Concurrent(effectors ...Effector) Effector {
 	}
 }
 
+// ConcurrentN returns an effector that runs the effectors concurrently with at most limit effectors at a time and
+// returns all errors that occurred as wrapped [errors.Join] error.
+// A limit of zero or less doesn't limit the number of concurrent effectors, like [Concurrent].
+// Panics of the effectors are recovered and returned as [*PanicError] in the joined error.
+//
+// Safe to use concurrently.
+func ConcurrentN(limit int, effectors ...Effector) Effector {
+	return func(ctx context.Context) error {
+		g, ctx := errgroup.WithContext(ctx)
+		if limit > 0 {
+			g.SetLimit(limit)
+		}
+
+		var mu sync.Mutex
+		var errs []error
+		for _, effector := range effectors {
+			g.Go(func() error {
+				err := Protector(effector).Do(ctx)
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+				return err
+			})
+		}
+		// The returned error is ignored here on purpose,
+		// as we are interested in all errors and not just the first one.
+		_ = g.Wait()
+		return errors.Join(errs...)
+	}
+}
+
 // Sequential returns an effector that runs the effectors sequentially and
 // returns all errors that occurred as wrapped [errors.Join] error.
 //
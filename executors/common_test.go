@@ -117,7 +117,7 @@ func TestEffector_Run(t *testing.T) {
 				},
 			},
 			wantErr: true,
-			errType: reflect.TypeOf(errors.Join(errors.New(""))),
+			errType: reflect.TypeOf(&PanicError{}),
 		},
 		{
 			name: "all policies with context timeout",
@@ -271,6 +271,18 @@ func TestParallel(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "panic",
+			effectors: []Effector{
+				func(ctx context.Context) error {
+					return nil
+				},
+				func(ctx context.Context) error {
+					panic("task panicked")
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -354,3 +366,79 @@ func TestSequential(t *testing.T) {
 		})
 	}
 }
+
+func TestEffector_Go_Panic(t *testing.T) {
+	err := <-Effector(func(context.Context) error { panic("test") }).Go()
+
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Errorf("Effector.Go() error = %v, want %T", err, panicErr)
+	}
+}
+
+func TestConcurrentN(t *testing.T) {
+	tests := []struct {
+		name     string
+		limit    int
+		count    int
+		failures int
+		wantMax  int32
+	}{
+		{
+			name:    "limited",
+			limit:   3,
+			count:   20,
+			wantMax: 3,
+		},
+		{
+			name:    "unlimited",
+			limit:   0,
+			count:   5,
+			wantMax: 5,
+		},
+		{
+			name:     "collects errors",
+			limit:    2,
+			count:    4,
+			failures: 4,
+			wantMax:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var running, peak atomic.Int32
+			effectors := make([]Effector, tt.count)
+			for i := range effectors {
+				effectors[i] = func(ctx context.Context) error {
+					n := running.Add(1)
+					defer running.Add(-1)
+					for {
+						p := peak.Load()
+						if n <= p || peak.CompareAndSwap(p, n) {
+							break
+						}
+					}
+					time.Sleep(time.Millisecond)
+					if i < tt.failures {
+						return errors.New("task failed")
+					}
+					return nil
+				}
+			}
+
+			err := ConcurrentN(tt.limit, effectors...).Do()
+			if (err != nil) != (tt.failures > 0) {
+				t.Fatalf("ConcurrentN() error = %v, wantErr %v", err, tt.failures > 0)
+			}
+			if err != nil {
+				if got := len(err.(interface{ Unwrap() []error }).Unwrap()); got != tt.failures {
+					t.Errorf("ConcurrentN() errors = %d, want %d", got, tt.failures)
+				}
+			}
+			if got := peak.Load(); got > tt.wantMax {
+				t.Errorf("ConcurrentN() ran %d effectors concurrently, want at most %d", got, tt.wantMax)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package executors
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCron is the error returned when a cron expression can't be parsed.
+type ErrInvalidCron struct {
+	// Expr is the invalid expression.
+	Expr string
+	// Reason describes why the expression is invalid.
+	Reason string
+}
+
+// Error returns the error message.
+func (e *ErrInvalidCron) Error() string {
+	return fmt.Sprintf("invalid cron expression %q: %s", e.Expr, e.Reason)
+}
+
+// cronDescriptors are the supported shorthands of cron expressions.
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronField describes a field of a cron expression.
+type cronField struct {
+	// name is the name of the field used in error messages.
+	name string
+	// low is the lowest allowed value.
+	low int
+	// high is the highest allowed value.
+	high int
+	// names are the aliases of the values, e.g. "jan" for 1.
+	names map[string]int
+}
+
+// cronFields are the fields of a cron expression in order.
+var cronFields = [5]cronField{
+	{name: "minute", low: 0, high: 59},
+	{name: "hour", low: 0, high: 23},
+	{name: "day of month", low: 1, high: 31},
+	{name: "month", low: 1, high: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}},
+	// Both 0 and 7 are Sunday.
+	{name: "day of week", low: 0, high: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}},
+}
+
+// cronSchedule is the [Schedule] of a parsed cron expression.
+// Every field is a bit set of the matching values.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// domStar and dowStar indicate that the day fields are unrestricted, i.e. "*" without a step.
+	domStar, dowStar bool
+}
+
+// cronSearchLimit is how far into the future the next run of a cron expression is searched.
+// Expressions like "0 0 30 2 *" never match and return the zero time.
+const cronSearchLimit = 5 * 366 * 24 * time.Hour
+
+// Cron parses a standard cron expression with the five fields minute, hour, day of month, month and day of week
+// and returns its [Schedule]. The times are evaluated in the location of the time passed to [Schedule.Next].
+//
+// Fields support "*", values, ranges ("1-5"), steps ("*/15", "0-30/10"), lists ("1,15") and the names of
+// months and weekdays ("jan", "mon"). The descriptors "@yearly", "@monthly", "@weekly", "@daily" and "@hourly"
+// are supported as well. If both day of month and day of week are restricted, a day matching either runs.
+func Cron(expr string) (Schedule, error) {
+	spec := strings.TrimSpace(expr)
+	if d, ok := cronDescriptors[strings.ToLower(spec)]; ok {
+		spec = d
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != len(cronFields) {
+		return nil, &ErrInvalidCron{Expr: expr, Reason: fmt.Sprintf("expected %d fields, got %d", len(cronFields), len(fields))}
+	}
+
+	var sets [5]uint64
+	for i, field := range fields {
+		set, err := cronFields[i].parse(field)
+		if err != nil {
+			return nil, &ErrInvalidCron{Expr: expr, Reason: err.Error()}
+		}
+		sets[i] = set
+	}
+
+	// Sunday may be written as 7.
+	dow := sets[4]
+	if dow&(1<<7) != 0 {
+		dow = dow&^(1<<7) | 1
+	}
+	return &cronSchedule{
+		minute:  sets[0],
+		hour:    sets[1],
+		dom:     sets[2],
+		month:   sets[3],
+		dow:     dow,
+		domStar: isCronStar(fields[2]),
+		dowStar: isCronStar(fields[4]),
+	}, nil
+}
+
+// isCronStar reports whether the field matches every value without a step, like "*".
+// A field with a step like "*/2" restricts the days, so it is combined with the other day field like any other value.
+func isCronStar(field string) bool {
+	return field == "*" || field == "*/1"
+}
+
+// parse parses a comma separated list of ranges into a bit set.
+func (f cronField) parse(field string) (uint64, error) {
+	var set uint64
+	for part := range strings.SplitSeq(field, ",") {
+		r, step, hasStep := strings.Cut(part, "/")
+
+		low, high := f.low, f.high
+		if r != "*" {
+			var err error
+			first, last, isRange := strings.Cut(r, "-")
+			if low, err = f.value(first); err != nil {
+				return 0, err
+			}
+			high = low
+			switch {
+			case isRange:
+				if high, err = f.value(last); err != nil {
+					return 0, err
+				}
+			case hasStep:
+				// "a/n" runs from a to the end of the field.
+				high = f.high
+			}
+		}
+		if low > high {
+			return 0, fmt.Errorf("%s range %q is reversed", f.name, part)
+		}
+
+		n := 1
+		if hasStep {
+			var err error
+			if n, err = strconv.Atoi(step); err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid %s step %q", f.name, step)
+			}
+		}
+		for v := low; v <= high; v += n {
+			set |= 1 << v
+		}
+	}
+	return set, nil
+}
+
+// value parses a single value or name of the field.
+func (f cronField) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.low || v > f.high {
+		return 0, fmt.Errorf("invalid %s %q", f.name, s)
+	}
+	return v, nil
+}
+
+// Next returns the first time after the given time that matches the expression.
+func (c *cronSchedule) Next(after time.Time) time.Time {
+	loc := after.Location()
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(cronSearchLimit)
+
+	for t.Before(limit) {
+		switch {
+		case !has(c.month, int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !c.matchDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case !has(c.hour, t.Hour()):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case !has(c.minute, t.Minute()):
+			t = t.Truncate(time.Minute).Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// matchDay reports whether the day of the time matches the day of month and day of week fields.
+func (c *cronSchedule) matchDay(t time.Time) bool {
+	dom := has(c.dom, t.Day())
+	dow := has(c.dow, int(t.Weekday()))
+	if c.domStar || c.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// has reports whether the value is in the bit set.
+func has(set uint64, v int) bool {
+	return set&(1<<v) != 0
+}
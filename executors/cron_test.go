@@ -0,0 +1,144 @@
+package executors
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCron(t *testing.T) {
+	// 2026-03-02 is a Monday.
+	from := time.Date(2026, time.March, 2, 10, 30, 15, 0, time.UTC)
+	tests := []struct {
+		name    string
+		expr    string
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name: "every minute",
+			expr: "* * * * *",
+			want: time.Date(2026, time.March, 2, 10, 31, 0, 0, time.UTC),
+		},
+		{
+			name: "step",
+			expr: "*/15 * * * *",
+			want: time.Date(2026, time.March, 2, 10, 45, 0, 0, time.UTC),
+		},
+		{
+			name: "list and range",
+			expr: "0 8,12-14 * * *",
+			want: time.Date(2026, time.March, 2, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "next day",
+			expr: "0 9 * * *",
+			want: time.Date(2026, time.March, 3, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "weekday names",
+			expr: "0 9 * * fri",
+			want: time.Date(2026, time.March, 6, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "sunday as seven",
+			expr: "0 0 * * 7",
+			want: time.Date(2026, time.March, 8, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "day of month or day of week",
+			expr: "0 0 15 * sun",
+			want: time.Date(2026, time.March, 8, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "hour step",
+			expr: "0 */6 * * *",
+			want: time.Date(2026, time.March, 2, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "day of month step",
+			expr: "0 0 */10 * *",
+			want: time.Date(2026, time.March, 11, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "day of month step or day of week",
+			expr: "0 0 */10 * sun",
+			want: time.Date(2026, time.March, 8, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "day of week step",
+			expr: "0 0 * * */3",
+			want: time.Date(2026, time.March, 4, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "month names",
+			expr: "0 0 1 jun *",
+			want: time.Date(2026, time.June, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "value with step",
+			expr: "50/5 * * * *",
+			want: time.Date(2026, time.March, 2, 10, 50, 0, 0, time.UTC),
+		},
+		{
+			name: "descriptor",
+			expr: "@monthly",
+			want: time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "never matches",
+			expr: "0 0 30 2 *",
+			want: time.Time{},
+		},
+		{
+			name:    "too few fields",
+			expr:    "* * * *",
+			wantErr: true,
+		},
+		{
+			name:    "value out of range",
+			expr:    "60 * * * *",
+			wantErr: true,
+		},
+		{
+			name:    "reversed range",
+			expr:    "* 10-5 * * *",
+			wantErr: true,
+		},
+		{
+			name:    "invalid step",
+			expr:    "*/0 * * * *",
+			wantErr: true,
+		},
+		{
+			name:    "missing step",
+			expr:    "*/ * * * *",
+			wantErr: true,
+		},
+		{
+			name:    "unknown name",
+			expr:    "* * * foo *",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := Cron(tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Cron() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				var cronErr *ErrInvalidCron
+				if !errors.As(err, &cronErr) {
+					t.Errorf("Cron() error = %T, want %T", err, cronErr)
+				}
+				return
+			}
+
+			if got := schedule.Next(from); !got.Equal(tt.want) {
+				t.Errorf("Cron().Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
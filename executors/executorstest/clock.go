@@ -0,0 +1,225 @@
+// Package executorstest provides utilities to test the policies of the executors package without waiting.
+package executorstest
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/lvlcn-t/go-kit/executors"
+)
+
+var _ executors.Clock = (*FakeClock)(nil)
+
+// FakeClock is an [executors.Clock] whose time only moves when it is advanced manually.
+// Timers fire and the functions of AfterFunc are called by [FakeClock.Advance] before it returns.
+//
+// Safe to use concurrently.
+type FakeClock struct {
+	// mu protects the fields below.
+	mu sync.Mutex
+	// cond is signaled whenever the timers or the running state changes.
+	cond *sync.Cond
+	// now is the current time of the clock.
+	now time.Time
+	// timers are the pending timers.
+	timers []*fakeTimer
+}
+
+// NewFakeClock creates a new [FakeClock] that starts at the given time.
+// A zero start time is replaced with a fixed date, so the times in tests are reproducible.
+func NewFakeClock(start time.Time) *FakeClock {
+	if start.IsZero() {
+		start = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	}
+	c := &FakeClock{now: start}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now returns the current time of the clock.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTimer creates a new timer that fires once the clock was advanced by the duration.
+func (c *FakeClock) NewTimer(d time.Duration) executors.Timer {
+	t := &fakeTimer{clock: c, c: make(chan time.Time, 1)}
+	t.Reset(d)
+	return t
+}
+
+// AfterFunc calls f once the clock was advanced by the duration.
+func (c *FakeClock) AfterFunc(d time.Duration, f func()) executors.Timer {
+	t := &fakeTimer{clock: c, f: f}
+	t.Reset(d)
+	return t
+}
+
+// Advance moves the clock forward by the duration and fires all timers that are due in the order of their time.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	target := c.now.Add(d)
+	for len(c.timers) > 0 && !c.timers[0].at.After(target) {
+		t := c.timers[0]
+		c.timers = c.timers[1:]
+		if t.at.After(c.now) {
+			c.now = t.at
+		}
+		c.cond.Broadcast()
+
+		// The timer fires without the lock, so its function can use the clock.
+		now := c.now
+		c.mu.Unlock()
+		t.fire(now)
+		c.mu.Lock()
+	}
+	if target.After(c.now) {
+		c.now = target
+	}
+	c.mu.Unlock()
+}
+
+// AdvanceNext moves the clock forward to the next pending timer and fires it.
+// Returns the duration the clock was advanced by, which is zero if no timer is pending.
+func (c *FakeClock) AdvanceNext() time.Duration {
+	c.mu.Lock()
+	if len(c.timers) == 0 {
+		c.mu.Unlock()
+		return 0
+	}
+	d := max(c.timers[0].at.Sub(c.now), 0)
+	c.mu.Unlock()
+
+	c.Advance(d)
+	return d
+}
+
+// Timers returns the number of pending timers.
+func (c *FakeClock) Timers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.timers)
+}
+
+// BlockUntil blocks until at least n timers are pending,
+// e.g. until a retrier waits for its next attempt.
+func (c *FakeClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.timers) < n {
+		c.cond.Wait()
+	}
+}
+
+// Run calls the effector and advances the clock to the next pending timer whenever one is pending,
+// until the effector returns. This runs effectors that wait for delays like a [executors.Retrier] without waiting.
+//
+// Timers that run concurrently with the effector, like the one of a [executors.Timeouter], fire as soon as they are pending.
+func (c *FakeClock) Run(ctx context.Context, effector executors.Effector) error {
+	done := false
+	result := make(chan error, 1)
+	go func() {
+		err := effector(ctx)
+		c.mu.Lock()
+		done = true
+		c.cond.Broadcast()
+		c.mu.Unlock()
+		result <- err
+	}()
+
+	for {
+		c.mu.Lock()
+		for len(c.timers) == 0 && !done {
+			c.cond.Wait()
+		}
+		if done {
+			c.mu.Unlock()
+			return <-result
+		}
+		c.mu.Unlock()
+		c.AdvanceNext()
+	}
+}
+
+// schedule adds the timer to the pending timers sorted by their time.
+// Must be called with the lock held.
+func (c *FakeClock) schedule(t *fakeTimer) {
+	i, _ := slices.BinarySearchFunc(c.timers, t.at, func(p *fakeTimer, at time.Time) int {
+		// Timers with the same time fire in the order they were scheduled.
+		if p.at.After(at) {
+			return 1
+		}
+		return -1
+	})
+	c.timers = slices.Insert(c.timers, i, t)
+	c.cond.Broadcast()
+}
+
+// unschedule removes the timer from the pending timers and reports whether it was pending.
+// Must be called with the lock held.
+func (c *FakeClock) unschedule(t *fakeTimer) bool {
+	i := slices.Index(c.timers, t)
+	if i < 0 {
+		return false
+	}
+	c.timers = slices.Delete(c.timers, i, i+1)
+	c.cond.Broadcast()
+	return true
+}
+
+// fakeTimer is the [executors.Timer] of a [FakeClock].
+type fakeTimer struct {
+	// clock is the clock the timer belongs to.
+	clock *FakeClock
+	// at is the time the timer fires.
+	at time.Time
+	// c is the channel the time is sent on. It is nil for timers created with AfterFunc.
+	c chan time.Time
+	// f is the function called when the timer fires. It is nil for timers created with NewTimer.
+	f func()
+}
+
+// C returns the channel on which the time is delivered.
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+// Stop prevents the timer from firing.
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	return t.clock.unschedule(t)
+}
+
+// Reset changes the timer to fire once the clock was advanced by the duration.
+// Like a [time.Timer], the timer fires immediately if the duration is not positive.
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	pending := t.clock.unschedule(t)
+	t.at = t.clock.now.Add(d)
+	if d <= 0 {
+		t.clock.mu.Unlock()
+		t.fire(t.at)
+		return pending
+	}
+	t.clock.schedule(t)
+	t.clock.mu.Unlock()
+	return pending
+}
+
+// fire delivers the time or calls the function of the timer.
+func (t *fakeTimer) fire(now time.Time) {
+	if t.f != nil {
+		t.f()
+		return
+	}
+	// Like a [time.Timer], the channel holds at most one time.
+	select {
+	case t.c <- now:
+	default:
+	}
+}
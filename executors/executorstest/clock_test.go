@@ -0,0 +1,251 @@
+package executorstest
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lvlcn-t/go-kit/executors"
+)
+
+func TestFakeClock_Advance(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		timers  []time.Duration
+		stopped int
+		advance time.Duration
+		want    []bool
+	}{
+		{
+			name:    "nothing due",
+			timers:  []time.Duration{time.Second, 2 * time.Second},
+			stopped: -1,
+			advance: 500 * time.Millisecond,
+			want:    []bool{false, false},
+		},
+		{
+			name:    "some due",
+			timers:  []time.Duration{time.Second, 2 * time.Second, 3 * time.Second},
+			stopped: -1,
+			advance: 2 * time.Second,
+			want:    []bool{true, true, false},
+		},
+		{
+			name:    "stopped timer",
+			timers:  []time.Duration{time.Second, time.Second},
+			stopped: 0,
+			advance: time.Second,
+			want:    []bool{false, true},
+		},
+		{
+			name:    "immediate timer",
+			timers:  []time.Duration{0},
+			stopped: -1,
+			advance: 0,
+			want:    []bool{true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewFakeClock(time.Time{})
+			start := c.Now()
+
+			timers := make([]executors.Timer, len(tt.timers))
+			for i, d := range tt.timers {
+				timers[i] = c.NewTimer(d)
+			}
+			if tt.stopped >= 0 && !timers[tt.stopped].Stop() {
+				t.Fatal("Timer.Stop() = false, want true")
+			}
+
+			c.Advance(tt.advance)
+			if got := c.Now().Sub(start); got != tt.advance {
+				t.Errorf("FakeClock.Now() advanced by %v, want %v", got, tt.advance)
+			}
+			for i, timer := range timers {
+				select {
+				case <-timer.C():
+					if !tt.want[i] {
+						t.Errorf("timer %d fired, want not fired", i)
+					}
+				default:
+					if tt.want[i] {
+						t.Errorf("timer %d didn't fire, want fired", i)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestFakeClock_AfterFunc(t *testing.T) {
+	t.Parallel()
+
+	c := NewFakeClock(time.Time{})
+	var order []int
+	c.AfterFunc(2*time.Second, func() { order = append(order, 2) })
+	c.AfterFunc(time.Second, func() { order = append(order, 1) })
+	reset := c.AfterFunc(time.Second, func() { order = append(order, 3) })
+	if !reset.Reset(3 * time.Second) {
+		t.Error("Timer.Reset() = false, want true")
+	}
+
+	if got := c.AdvanceNext(); got != time.Second {
+		t.Errorf("FakeClock.AdvanceNext() = %v, want %v", got, time.Second)
+	}
+	c.Advance(5 * time.Second)
+	if len(order) != 3 || order[0] != 1 || order[1] != 2 || order[2] != 3 {
+		t.Errorf("functions called in order %v, want [1 2 3]", order)
+	}
+	if got := c.Timers(); got != 0 {
+		t.Errorf("FakeClock.Timers() = %d, want 0", got)
+	}
+	if got := c.AdvanceNext(); got != 0 {
+		t.Errorf("FakeClock.AdvanceNext() = %v, want 0", got)
+	}
+}
+
+func TestFakeClock_BlockUntil(t *testing.T) {
+	t.Parallel()
+
+	c := NewFakeClock(time.Time{})
+	var fired atomic.Bool
+	go func() {
+		timer := c.NewTimer(time.Minute)
+		<-timer.C()
+		fired.Store(true)
+	}()
+
+	c.BlockUntil(1)
+	if fired.Load() {
+		t.Fatal("timer fired before the clock was advanced")
+	}
+	c.Advance(time.Minute)
+	c.BlockUntil(0)
+}
+
+func TestFakeClock_Run(t *testing.T) {
+	t.Parallel()
+
+	errFail := errors.New("fail")
+	tests := []struct {
+		name       string
+		effector   func(c *FakeClock, r *Recorder) executors.Effector
+		errs       []error
+		wantErr    error
+		wantDelays []time.Duration
+	}{
+		{
+			name: "retrier",
+			effector: func(c *FakeClock, r *Recorder) executors.Effector {
+				retrier := &executors.Retrier{
+					MaxRetries: 4,
+					Backoff:    executors.ExponentialBackoff(time.Second, time.Minute),
+					Clock:      c,
+				}
+				return retrier.Retry(r.Effector())
+			},
+			errs:       []error{errFail, errFail, errFail},
+			wantDelays: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second},
+		},
+		{
+			name: "retrier with max elapsed time",
+			effector: func(c *FakeClock, r *Recorder) executors.Effector {
+				retrier := &executors.Retrier{
+					MaxRetries:     10,
+					Backoff:        func(uint) time.Duration { return time.Minute },
+					MaxElapsedTime: 150 * time.Second,
+					Clock:          c,
+				}
+				return retrier.Retry(r.Effector())
+			},
+			errs:       []error{errFail, errFail, errFail},
+			wantErr:    errFail,
+			wantDelays: []time.Duration{time.Minute, time.Minute},
+		},
+		{
+			name: "rate limiter",
+			effector: func(c *FakeClock, r *Recorder) executors.Effector {
+				limited := executors.RateLimiter(executors.RateLimit(2), r.Effector(), executors.WithClock(c))
+				return func(ctx context.Context) error {
+					for range 3 {
+						if err := limited(ctx); err != nil {
+							return err
+						}
+					}
+					return nil
+				}
+			},
+			wantDelays: []time.Duration{500 * time.Millisecond, 500 * time.Millisecond},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewFakeClock(time.Time{})
+			r := NewRecorder(c, tt.errs...)
+
+			err := c.Run(context.Background(), tt.effector(c, r))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("FakeClock.Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			AssertAttempts(t, r, len(tt.wantDelays)+1)
+			AssertDelays(t, r, tt.wantDelays...)
+		})
+	}
+}
+
+func TestFakeClock_Timeouter(t *testing.T) {
+	t.Parallel()
+
+	c := NewFakeClock(time.Time{})
+	effector := executors.Timeouter(time.Second, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, executors.WithClock(c))
+
+	done := make(chan error, 1)
+	go func() { done <- effector(context.Background()) }()
+
+	c.BlockUntil(1)
+	c.Advance(999 * time.Millisecond)
+	select {
+	case err := <-done:
+		t.Fatalf("Timeouter() returned %v before the timeout", err)
+	default:
+	}
+
+	c.Advance(time.Millisecond)
+	if err := <-done; !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Timeouter() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestFakeClock_Breaker(t *testing.T) {
+	t.Parallel()
+
+	c := NewFakeClock(time.Time{})
+	breaker := executors.NewBreaker(executors.BreakerOptions{
+		MaxFailures:  1,
+		ResetTimeout: time.Minute,
+		Clock:        c,
+	})
+	_ = breaker.Wrap(func(context.Context) error { return errors.New("fail") })(context.Background())
+
+	if got := breaker.State(); got != executors.StateOpen {
+		t.Fatalf("Breaker.State() = %v, want %v", got, executors.StateOpen)
+	}
+	c.Advance(59 * time.Second)
+	if got := breaker.State(); got != executors.StateOpen {
+		t.Errorf("Breaker.State() = %v, want %v", got, executors.StateOpen)
+	}
+	c.Advance(time.Second)
+	if got := breaker.State(); got != executors.StateHalfOpen {
+		t.Errorf("Breaker.State() = %v, want %v", got, executors.StateHalfOpen)
+	}
+}
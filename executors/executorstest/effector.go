@@ -0,0 +1,93 @@
+package executorstest
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lvlcn-t/go-kit/executors"
+)
+
+// Recorder records the calls of an effector with the time of a clock,
+// so the attempts and the delays between them can be asserted.
+//
+// Safe to use concurrently.
+type Recorder struct {
+	// clock is the clock the calls are recorded with.
+	clock executors.Clock
+	// errs are the errors returned by the calls in order.
+	errs []error
+
+	// mu protects the calls.
+	mu sync.Mutex
+	// calls are the times of the calls.
+	calls []time.Time
+}
+
+// NewRecorder creates a new [Recorder] whose n-th call returns the n-th error.
+// All calls after the last error succeed.
+func NewRecorder(clock executors.Clock, errs ...error) *Recorder {
+	if clock == nil {
+		clock = executors.RealClock()
+	}
+	return &Recorder{clock: clock, errs: errs}
+}
+
+// Effector returns the effector that records its calls.
+func (r *Recorder) Effector() executors.Effector {
+	return func(context.Context) error {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.calls = append(r.calls, r.clock.Now())
+		if i := len(r.calls) - 1; i < len(r.errs) {
+			return r.errs[i]
+		}
+		return nil
+	}
+}
+
+// Calls returns the times of all recorded calls.
+func (r *Recorder) Calls() []time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.calls)
+}
+
+// Attempts returns the number of recorded calls.
+func (r *Recorder) Attempts() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.calls)
+}
+
+// Delays returns the durations between consecutive calls.
+func (r *Recorder) Delays() []time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.calls) < 2 {
+		return nil
+	}
+	delays := make([]time.Duration, 0, len(r.calls)-1)
+	for i := 1; i < len(r.calls); i++ {
+		delays = append(delays, r.calls[i].Sub(r.calls[i-1]))
+	}
+	return delays
+}
+
+// AssertAttempts fails the test if the recorder didn't record exactly want calls.
+func AssertAttempts(t testing.TB, r *Recorder, want int) {
+	t.Helper()
+	if got := r.Attempts(); got != want {
+		t.Errorf("attempts = %d, want %d", got, want)
+	}
+}
+
+// AssertDelays fails the test if the durations between the recorded calls are not exactly the wanted delays.
+func AssertDelays(t testing.TB, r *Recorder, want ...time.Duration) {
+	t.Helper()
+	if got := r.Delays(); !slices.Equal(got, want) {
+		t.Errorf("delays = %v, want %v", got, want)
+	}
+}
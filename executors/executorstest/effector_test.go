@@ -0,0 +1,84 @@
+package executorstest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// fakeTB records the failures of a test.
+type fakeTB struct {
+	testing.TB
+	errors []string
+}
+
+// Helper marks the calling function as helper.
+func (*fakeTB) Helper() {}
+
+// Errorf records the failure.
+func (f *fakeTB) Errorf(format string, args ...any) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	errFail := errors.New("fail")
+	tests := []struct {
+		name         string
+		errs         []error
+		steps        []time.Duration
+		wantErrs     []error
+		wantAttempts int
+		wantDelays   []time.Duration
+		failAttempts int
+		failDelays   []time.Duration
+	}{
+		{
+			name:         "no calls",
+			wantAttempts: 0,
+			wantDelays:   nil,
+			failAttempts: 1,
+			failDelays:   []time.Duration{time.Second},
+		},
+		{
+			name:         "scripted errors",
+			errs:         []error{errFail, errFail},
+			steps:        []time.Duration{0, time.Second, 3 * time.Second},
+			wantErrs:     []error{errFail, errFail, nil},
+			wantAttempts: 3,
+			wantDelays:   []time.Duration{time.Second, 3 * time.Second},
+			failAttempts: 2,
+			failDelays:   []time.Duration{time.Second},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewFakeClock(time.Time{})
+			r := NewRecorder(c, tt.errs...)
+			effector := r.Effector()
+			for i, step := range tt.steps {
+				c.Advance(step)
+				if err := effector(context.Background()); !errors.Is(err, tt.wantErrs[i]) {
+					t.Errorf("call %d error = %v, want %v", i, err, tt.wantErrs[i])
+				}
+			}
+
+			AssertAttempts(t, r, tt.wantAttempts)
+			AssertDelays(t, r, tt.wantDelays...)
+			if got := len(r.Calls()); got != tt.wantAttempts {
+				t.Errorf("len(Recorder.Calls()) = %d, want %d", got, tt.wantAttempts)
+			}
+
+			tb := &fakeTB{}
+			AssertAttempts(tb, r, tt.failAttempts)
+			AssertDelays(tb, r, tt.failDelays...)
+			if len(tb.errors) != 2 {
+				t.Errorf("assertions reported %v, want 2 failures", tb.errors)
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package executors
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Func is a function that performs an action and returns a value and an error.
+// It is the typed counterpart of [Effector] and supports the same policies,
+// so that a value doesn't have to be smuggled out of a policy-wrapped call through a closure variable.
+type Func[T any] func(ctx context.Context) (T, error)
+
+// Result is the outcome of a [Func].
+type Result[T any] struct {
+	// Value is the value returned by the func.
+	Value T
+	// Err is the error returned by the func.
+	Err error
+}
+
+// Do runs the func and returns its value and error.
+// If a context is provided, it is used, otherwise a new context is created.
+func (f Func[T]) Do(ctx ...context.Context) (T, error) {
+	if len(ctx) > 0 {
+		return f(ctx[0])
+	}
+	return f(context.Background())
+}
+
+// Go runs the func concurrently and returns a channel that will receive the result.
+// The channel is closed when the func finishes.
+// A panic of the func is recovered and received as [*PanicError].
+func (f Func[T]) Go(ctx ...context.Context) <-chan Result[T] {
+	ch := make(chan Result[T], 1)
+	go func() {
+		v, err := f.WithProtection().Do(ctx...)
+		ch <- Result[T]{Value: v, Err: err}
+		close(ch)
+	}()
+	return ch
+}
+
+// Effector returns an effector that runs the func and discards its value.
+func (f Func[T]) Effector() Effector {
+	return func(ctx context.Context) error {
+		_, err := f(ctx)
+		return err
+	}
+}
+
+// WithRetry returns a func that retries the func a number of times with a delay between each retry.
+func (f Func[T]) WithRetry(retrier Retrier) Func[T] {
+	return Apply(f, retrier.Retry)
+}
+
+// WithTimeout returns a func that runs the func with a timeout.
+func (f Func[T]) WithTimeout(timeout time.Duration, opts ...Option) Func[T] {
+	return Apply(f, func(e Effector) Effector { return Timeouter(timeout, e, opts...) })
+}
+
+// WithRateLimit returns a func that runs the func with the specified rate limit.
+func (f Func[T]) WithRateLimit(r rate.Limit, opts ...Option) Func[T] {
+	return Apply(f, func(e Effector) Effector { return RateLimiter(r, e, opts...) })
+}
+
+// WithLimiter returns a func that runs the func once the given [Limiter] permits it.
+func (f Func[T]) WithLimiter(l Limiter, opts ...Option) Func[T] {
+	return Apply(f, func(e Effector) Effector { return Limited(l, e, opts...) })
+}
+
+// WithKeyedRateLimit returns a func that runs the func with the specified rate limit per key.
+func (f Func[T]) WithKeyedRateLimit(keyFunc KeyFunc, r rate.Limit, opts ...Option) Func[T] {
+	return Apply(f, func(e Effector) Effector { return KeyedRateLimiter(keyFunc, r, e, opts...) })
+}
+
+// WithCircuitBreaker returns a func that stops calling the task if it fails a certain number of times, until a certain amount of time has passed.
+func (f Func[T]) WithCircuitBreaker(maxFailures int, resetTimeout time.Duration) Func[T] {
+	return Apply(f, func(e Effector) Effector { return CircuitBreaker(maxFailures, resetTimeout, e) })
+}
+
+// WithKeyedCircuitBreaker returns a func that runs the func with a circuit breaker per key.
+func (f Func[T]) WithKeyedCircuitBreaker(keyFunc KeyFunc, maxFailures int, resetTimeout time.Duration, opts ...Option) Func[T] {
+	return Apply(f, func(e Effector) Effector { return KeyedCircuitBreaker(keyFunc, maxFailures, resetTimeout, e, opts...) })
+}
+
+// WithBreaker returns a func that runs the func through the given [Breaker].
+func (f Func[T]) WithBreaker(b *Breaker) Func[T] {
+	return Apply(f, b.Wrap)
+}
+
+// WithBulkhead returns a func that limits the number of concurrent calls of the func.
+func (f Func[T]) WithBulkhead(maxConcurrent, maxQueue int, opts ...Option) Func[T] {
+	return Apply(f, func(e Effector) Effector { return Bulkhead(maxConcurrent, maxQueue, e, opts...) })
+}
+
+// WithAdaptiveLimit returns a func that runs the func within the adaptive concurrency limit of the given [AdaptiveLimiter].
+func (f Func[T]) WithAdaptiveLimit(a *AdaptiveLimiter) Func[T] {
+	return Apply(f, a.Wrap)
+}
+
+// WithHedge returns a func that starts up to maxHedges duplicate calls of the func
+// if no call has succeeded after the given delay and returns the value of the first success.
+func (f Func[T]) WithHedge(delay time.Duration, maxHedges int, opts ...Option) Func[T] {
+	return Apply(f, func(e Effector) Effector { return Hedge(delay, maxHedges, e, opts...) })
+}
+
+// WithPolicy returns a func that runs the func with all policies configured by the given [Policy].
+func (f Func[T]) WithPolicy(p *Policy, opts ...Option) Func[T] {
+	return Apply(f, func(e Effector) Effector { return p.Apply(e, opts...) })
+}
+
+// WithSingleFlight returns a func that collapses concurrent calls with the same key into one execution and shares its value.
+func (f Func[T]) WithSingleFlight(keyFunc KeyFunc) Func[T] {
+	return SingleFlightFunc(keyFunc, f)
+}
+
+// WithProtection returns a func that recovers from panics and returns them as errors.
+func (f Func[T]) WithProtection(opts ...Option) Func[T] {
+	return Apply(f, func(e Effector) Effector { return Protector(e, opts...) })
+}
+
+// WithFallback returns a func that runs the fallback func if the first one returns an error.
+// Unlike [Effector.WithFallback], the value of the fallback is returned without an error if the fallback succeeds.
+// If the fallback fails too, both errors are returned as wrapped [errors.Join] error.
+func (f Func[T]) WithFallback(fallback Func[T]) Func[T] {
+	return func(ctx context.Context) (T, error) {
+		v, err := f(ctx)
+		if err == nil {
+			return v, nil
+		}
+		fv, fErr := fallback(ctx)
+		if fErr != nil {
+			return fv, errors.Join(err, fErr)
+		}
+		return fv, nil
+	}
+}
+
+// Apply applies an [Effector] policy to the func. The policy is applied once,
+// so that stateful policies like a [Breaker] share their state between all calls of the returned func.
+//
+// Example:
+//
+//	fetch := executors.Func[*User](fetchUser)
+//	fetch = executors.Apply(fetch, func(e executors.Effector) executors.Effector {
+//		return executors.Bulkhead(10, 100, e)
+//	})
+func Apply[T any](f Func[T], policy func(Effector) Effector) Func[T] {
+	if f == nil {
+		f = func(context.Context) (v T, err error) { return v, err }
+	}
+
+	effector := policy(func(ctx context.Context) error {
+		v, err := f(ctx)
+		if s, ok := ctx.Value(resultKey[T]{}).(*resultSlot[T]); ok {
+			s.set(v, err)
+		}
+		return err
+	})
+	return func(ctx context.Context) (T, error) {
+		s := &resultSlot[T]{}
+		err := effector(context.WithValue(ctx, resultKey[T]{}, s))
+		return s.get(), err
+	}
+}
+
+// resultKey is the context key of the [resultSlot] of a call.
+type resultKey[T any] struct{}
+
+// resultSlot holds the value of a call passed through an [Effector] policy.
+// Policies like [Hedge] may call the func concurrently, so the slot is protected by a mutex.
+type resultSlot[T any] struct {
+	mu sync.Mutex
+	// value is the value of the latest call or of the first successful call.
+	value T
+	// done is true once a call succeeded.
+	done bool
+}
+
+// set stores the value unless a successful value has already been stored.
+func (s *resultSlot[T]) set(v T, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done {
+		return
+	}
+	s.value = v
+	s.done = err == nil
+}
+
+// get returns the stored value.
+func (s *resultSlot[T]) get() T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.value
+}
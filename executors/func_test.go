@@ -0,0 +1,186 @@
+package executors
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFunc_Policies(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	tests := []struct {
+		name    string
+		fn      func(calls *atomic.Int32) Func[int]
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "success",
+			fn: func(calls *atomic.Int32) Func[int] {
+				return func(ctx context.Context) (int, error) { return 42, nil }
+			},
+			want: 42,
+		},
+		{
+			name: "retry returns value of successful attempt",
+			fn: func(calls *atomic.Int32) Func[int] {
+				return Func[int](func(ctx context.Context) (int, error) {
+					n := int(calls.Add(1))
+					if n < 3 {
+						return n, errFailed
+					}
+					return n, nil
+				}).WithRetry(Retrier{MaxRetries: 3, Backoff: ConstantBackoff(0)})
+			},
+			want: 3,
+		},
+		{
+			name: "timeout",
+			fn: func(calls *atomic.Int32) Func[int] {
+				return Func[int](func(ctx context.Context) (int, error) {
+					<-ctx.Done()
+					return 0, ctx.Err()
+				}).WithTimeout(time.Millisecond)
+			},
+			wantErr: true,
+		},
+		{
+			name: "rate limit, bulkhead and protection",
+			fn: func(calls *atomic.Int32) Func[int] {
+				return Func[int](func(ctx context.Context) (int, error) { return 7, nil }).
+					WithRateLimit(10).
+					WithBulkhead(1, 0).
+					WithProtection()
+			},
+			want: 7,
+		},
+		{
+			name: "protection",
+			fn: func(calls *atomic.Int32) Func[int] {
+				return Func[int](func(ctx context.Context) (int, error) { panic("boom") }).WithProtection()
+			},
+			wantErr: true,
+		},
+		{
+			name: "hedge returns value of winner",
+			fn: func(calls *atomic.Int32) Func[int] {
+				return Func[int](func(ctx context.Context) (int, error) {
+					if calls.Add(1) == 1 {
+						<-ctx.Done()
+						return -1, ctx.Err()
+					}
+					return 2, nil
+				}).WithHedge(time.Millisecond, 1)
+			},
+			want: 2,
+		},
+		{
+			name: "fallback value",
+			fn: func(calls *atomic.Int32) Func[int] {
+				return Func[int](func(ctx context.Context) (int, error) { return 0, errFailed }).
+					WithFallback(func(ctx context.Context) (int, error) { return 5, nil })
+			},
+			want: 5,
+		},
+		{
+			name: "fallback error",
+			fn: func(calls *atomic.Int32) Func[int] {
+				return Func[int](func(ctx context.Context) (int, error) { return 0, errFailed }).
+					WithFallback(func(ctx context.Context) (int, error) { return 0, errFailed })
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			got, err := tt.fn(&calls).Do(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Func.Do() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Func.Do() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFunc_WithCircuitBreaker_SharesState(t *testing.T) {
+	var calls atomic.Int32
+	fn := Func[string](func(ctx context.Context) (string, error) {
+		calls.Add(1)
+		return "", errors.New("failed")
+	}).WithCircuitBreaker(2, time.Hour)
+
+	for range 5 {
+		_, _ = fn.Do()
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("Func.WithCircuitBreaker() calls = %d, want 2", got)
+	}
+
+	_, err := fn.Do()
+	if !errors.Is(err, ErrCircuitOpen{}) {
+		t.Errorf("Func.WithCircuitBreaker() error = %v, want %v", err, ErrCircuitOpen{})
+	}
+}
+
+func TestFunc_Go(t *testing.T) {
+	fn := Func[string](func(ctx context.Context) (string, error) { return "done", nil })
+	results := []<-chan Result[string]{fn.Go(), fn.Go(context.Background())}
+
+	for _, ch := range results {
+		res := <-ch
+		if res.Err != nil || res.Value != "done" {
+			t.Errorf("Func.Go() = %+v, want done", res)
+		}
+		if _, ok := <-ch; ok {
+			t.Errorf("Func.Go() channel not closed")
+		}
+	}
+}
+
+func TestFunc_Go_Panic(t *testing.T) {
+	res := <-Func[string](func(context.Context) (string, error) { panic("test") }).Go()
+
+	var panicErr *PanicError
+	if !errors.As(res.Err, &panicErr) {
+		t.Errorf("Func.Go() error = %v, want %T", res.Err, panicErr)
+	}
+}
+
+func TestFunc_Effector(t *testing.T) {
+	var got int
+	fn := Func[int](func(ctx context.Context) (int, error) {
+		got = 1
+		return got, nil
+	})
+
+	if err := Concurrent(fn.Effector()).Do(); err != nil {
+		t.Fatalf("Func.Effector() error = %v", err)
+	}
+	if got != 1 {
+		t.Errorf("Func.Effector() did not run the func")
+	}
+}
+
+func TestApply_NestedFuncs(t *testing.T) {
+	inner := Func[int](func(ctx context.Context) (int, error) { return 1, nil }).WithTimeout(time.Second)
+	outer := Func[int](func(ctx context.Context) (int, error) {
+		v, err := inner(ctx)
+		return v + 1, err
+	}).WithRetry(Retrier{MaxRetries: 1})
+
+	got, err := outer.Do()
+	if err != nil || got != 2 {
+		t.Errorf("Apply() = %v, %v, want 2, nil", got, err)
+	}
+
+	if v, err := Apply[int](nil, Retry).Do(); v != 0 || err != nil {
+		t.Errorf("Apply() with nil func = %v, %v, want 0, nil", v, err)
+	}
+}
@@ -0,0 +1,338 @@
+package executors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrDuplicateNode is the error returned when a node is added to a [Graph] twice.
+type ErrDuplicateNode struct {
+	// Node is the name of the node.
+	Node string
+}
+
+// Error returns the error message.
+func (e ErrDuplicateNode) Error() string {
+	return fmt.Sprintf("duplicate node %q", e.Node)
+}
+
+// ErrUnknownNode is the error returned when a node of a [Graph] depends on a node that doesn't exist.
+type ErrUnknownNode struct {
+	// Node is the name of the unknown node.
+	Node string
+	// Dependant is the name of the node that depends on the unknown node.
+	Dependant string
+}
+
+// Error returns the error message.
+func (e ErrUnknownNode) Error() string {
+	return fmt.Sprintf("node %q depends on unknown node %q", e.Dependant, e.Node)
+}
+
+// ErrCycle is the error returned when the dependencies of a [Graph] contain a cycle.
+type ErrCycle struct {
+	// Path are the nodes of the cycle, starting and ending with the same node.
+	Path []string
+}
+
+// Error returns the error message.
+func (e ErrCycle) Error() string {
+	return "dependency cycle: " + strings.Join(e.Path, " -> ")
+}
+
+// ErrDependencyFailed is the error of a node of a [Graph] that was skipped because a dependency didn't succeed.
+type ErrDependencyFailed struct {
+	// Dependency is the name of the dependency that didn't succeed.
+	Dependency string
+}
+
+// Error returns the error message.
+func (e ErrDependencyFailed) Error() string {
+	return fmt.Sprintf("dependency %q didn't succeed", e.Dependency)
+}
+
+// NodeStatus is the status of a node after a [Graph] has run.
+type NodeStatus int
+
+const (
+	// NodeSucceeded means that the effector of the node returned no error.
+	NodeSucceeded NodeStatus = iota
+	// NodeFailed means that the effector of the node returned an error.
+	NodeFailed
+	// NodeSkipped means that the node didn't run, because a dependency didn't succeed or the context was done.
+	NodeSkipped
+)
+
+// String returns the string representation of the status.
+func (s NodeStatus) String() string {
+	switch s {
+	case NodeSucceeded:
+		return "succeeded"
+	case NodeFailed:
+		return "failed"
+	case NodeSkipped:
+		return "skipped"
+	default:
+		return "unknown"
+	}
+}
+
+// NodeResult is the result of a node of a [Graph].
+type NodeResult struct {
+	// Status is the status of the node.
+	Status NodeStatus
+	// Err is the error of a failed node or the reason a node was skipped.
+	Err error
+	// Duration is the time the effector of the node ran.
+	Duration time.Duration
+}
+
+// Report are the results of all nodes of a [Graph] by name.
+type Report map[string]NodeResult
+
+// Graph runs named effectors in the order of their dependencies.
+// Nodes whose dependencies have succeeded run concurrently, up to the limit set with [Graph.SetLimit].
+// Nodes that depend on a failed or skipped node are skipped.
+// A panicking node fails with a [*PanicError].
+//
+// Example:
+//
+//	report, err := executors.NewGraph().
+//		Add("migrate", migrate).
+//		Add("seed", seed, "migrate").
+//		Add("warmup", warmup, "migrate").
+//		Run(ctx)
+//
+// The graph must not be modified while it runs.
+type Graph struct {
+	// nodes are the nodes by name.
+	nodes map[string]*graphNode
+	// order are the names of the nodes in the order they were added.
+	order []string
+	// errs are the errors that occurred while adding nodes.
+	errs []error
+	// limit is the maximum number of nodes running at once.
+	limit int
+	// opts are passed on to the [Protector] of every node.
+	opts []Option
+	// clock measures the durations of the nodes.
+	clock Clock
+}
+
+// graphNode is a node of a [Graph].
+type graphNode struct {
+	// effector is run by the node.
+	effector Effector
+	// dependencies are the names of the nodes that have to succeed before the node runs.
+	dependencies []string
+}
+
+// NewGraph creates a new empty [Graph].
+// The options, e.g. [WithClock] or [WithObserver], are passed on to the policies of the graph.
+func NewGraph(opts ...Option) *Graph {
+	return &Graph{nodes: map[string]*graphNode{}, opts: opts, clock: newOptions(opts).clock}
+}
+
+// Add adds a node with the given name that runs the effector once all dependencies have succeeded.
+// The dependencies may be added later. Invalid nodes are reported by [Graph.Validate] and [Graph.Run].
+func (g *Graph) Add(name string, effector Effector, dependsOn ...string) *Graph {
+	if g.nodes == nil {
+		g.nodes = map[string]*graphNode{}
+	}
+	if _, ok := g.nodes[name]; ok {
+		g.errs = append(g.errs, ErrDuplicateNode{Node: name})
+		return g
+	}
+	if effector == nil {
+		effector = noopEffector
+	}
+
+	g.nodes[name] = &graphNode{effector: effector, dependencies: dependsOn}
+	g.order = append(g.order, name)
+	return g
+}
+
+// SetLimit limits the number of nodes running at once. A limit of zero or less doesn't limit the nodes.
+func (g *Graph) SetLimit(n int) *Graph {
+	g.limit = n
+	return g
+}
+
+// Validate returns the errors of duplicate nodes, unknown dependencies and cycles.
+func (g *Graph) Validate() error {
+	errs := slices.Clone(g.errs)
+	for _, name := range g.order {
+		for _, dep := range g.nodes[name].dependencies {
+			if _, ok := g.nodes[dep]; !ok {
+				errs = append(errs, ErrUnknownNode{Node: dep, Dependant: name})
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return g.findCycle()
+}
+
+// findCycle returns an [ErrCycle] if the dependencies contain a cycle.
+func (g *Graph) findCycle() error {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(g.nodes))
+	var path []string
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			start := 0
+			for path[start] != name {
+				start++
+			}
+			return ErrCycle{Path: append(slices.Clone(path[start:]), name)}
+		}
+
+		state[name] = visiting
+		path = append(path, name)
+		for _, dep := range g.nodes[name].dependencies {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+		return nil
+	}
+
+	for _, name := range g.order {
+		if err := visit(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nodeDone is the result of a node sent to the coordinator of [Graph.Run].
+type nodeDone struct {
+	name   string
+	result NodeResult
+}
+
+// Run runs all nodes of the graph and returns the result of every node.
+// The returned error joins the errors of the failed nodes as [*ErrStep] and the context error if nodes were skipped because of it.
+// If the graph is invalid, no node runs and the validation error is returned.
+func (g *Graph) Run(ctx context.Context) (Report, error) {
+	if err := g.Validate(); err != nil {
+		return nil, err
+	}
+
+	pending := make(map[string]int, len(g.nodes))
+	dependants := make(map[string][]string, len(g.nodes))
+	blockedBy := make(map[string]string, len(g.nodes))
+	for _, name := range g.order {
+		pending[name] = len(g.nodes[name].dependencies)
+		for _, dep := range g.nodes[name].dependencies {
+			dependants[dep] = append(dependants[dep], name)
+		}
+	}
+
+	var sem chan struct{}
+	if g.limit > 0 {
+		sem = make(chan struct{}, g.limit)
+	}
+	done := make(chan nodeDone, len(g.nodes))
+	start := func(name string) {
+		go func() {
+			done <- nodeDone{name: name, result: g.runNode(ctx, name, sem)}
+		}()
+	}
+
+	for _, name := range g.order {
+		if pending[name] == 0 {
+			start(name)
+		}
+	}
+
+	report := make(Report, len(g.nodes))
+	var errs []error
+	var skippedByContext bool
+	// complete records the result of a node and starts or skips its dependants.
+	var complete func(name string, result NodeResult)
+	complete = func(name string, result NodeResult) {
+		report[name] = result
+		switch {
+		case result.Status == NodeFailed:
+			errs = append(errs, &ErrStep{Step: name, Err: result.Err})
+		case result.Status == NodeSkipped && ctx.Err() != nil && errors.Is(result.Err, ctx.Err()):
+			skippedByContext = true
+		}
+
+		for _, dependant := range dependants[name] {
+			if result.Status != NodeSucceeded {
+				if _, ok := blockedBy[dependant]; !ok {
+					blockedBy[dependant] = name
+				}
+			}
+			pending[dependant]--
+			if pending[dependant] > 0 {
+				continue
+			}
+			if dep, ok := blockedBy[dependant]; ok {
+				complete(dependant, NodeResult{Status: NodeSkipped, Err: ErrDependencyFailed{Dependency: dep}})
+				continue
+			}
+			start(dependant)
+		}
+	}
+
+	for len(report) < len(g.nodes) {
+		d := <-done
+		complete(d.name, d.result)
+	}
+
+	if skippedByContext {
+		errs = append(errs, ctx.Err())
+	}
+	return report, errors.Join(errs...)
+}
+
+// runNode runs the effector of the node once a slot of the semaphore is free.
+func (g *Graph) runNode(ctx context.Context, name string, sem chan struct{}) NodeResult {
+	if sem != nil {
+		select {
+		case sem <- struct{}{}:
+			defer func() { <-sem }()
+		case <-ctx.Done():
+			return NodeResult{Status: NodeSkipped, Err: ctx.Err()}
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return NodeResult{Status: NodeSkipped, Err: err}
+	}
+
+	clock := clockOrReal(g.clock)
+	start := clock.Now()
+	err := Protector(g.nodes[name].effector, g.opts...)(ctx)
+	result := NodeResult{Status: NodeSucceeded, Duration: clock.Now().Sub(start)}
+	if err != nil {
+		result.Status, result.Err = NodeFailed, err
+	}
+	return result
+}
+
+// Effector returns an effector that runs the graph and returns its error.
+func (g *Graph) Effector() Effector {
+	return func(ctx context.Context) error {
+		_, err := g.Run(ctx)
+		return err
+	}
+}
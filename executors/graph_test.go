@@ -0,0 +1,227 @@
+package executors
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGraph_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		graph   *Graph
+		wantErr error
+	}{
+		{
+			name:  "valid graph",
+			graph: NewGraph().Add("a", noopEffector).Add("b", noopEffector, "a").Add("c", noopEffector, "a", "b"),
+		},
+		{
+			name:    "duplicate node",
+			graph:   NewGraph().Add("a", noopEffector).Add("a", noopEffector),
+			wantErr: ErrDuplicateNode{Node: "a"},
+		},
+		{
+			name:    "unknown dependency",
+			graph:   NewGraph().Add("a", noopEffector, "b"),
+			wantErr: ErrUnknownNode{Node: "b", Dependant: "a"},
+		},
+		{
+			name:    "cycle",
+			graph:   NewGraph().Add("a", noopEffector, "c").Add("b", noopEffector, "a").Add("c", noopEffector, "b"),
+			wantErr: ErrCycle{},
+		},
+		{
+			name:    "self dependency",
+			graph:   NewGraph().Add("a", noopEffector, "a"),
+			wantErr: ErrCycle{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.graph.Validate()
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("Graph.Validate() error = %v, want nil", err)
+				}
+				return
+			}
+
+			if cycle, ok := tt.wantErr.(ErrCycle); ok {
+				if !errors.As(err, &cycle) || len(cycle.Path) < 2 || cycle.Path[0] != cycle.Path[len(cycle.Path)-1] {
+					t.Errorf("Graph.Validate() error = %v, want %T", err, tt.wantErr)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Graph.Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGraph_Run(t *testing.T) {
+	errTest := errors.New("test error")
+	fail := func(context.Context) error { return errTest }
+
+	tests := []struct {
+		name       string
+		graph      func(record func(string) Effector) *Graph
+		wantStatus map[string]NodeStatus
+		wantBefore [][2]string
+		wantErr    bool
+	}{
+		{
+			name: "runs in dependency order",
+			graph: func(record func(string) Effector) *Graph {
+				return NewGraph().
+					Add("seed", record("seed"), "migrate").
+					Add("migrate", record("migrate")).
+					Add("warmup", record("warmup"), "migrate", "seed")
+			},
+			wantStatus: map[string]NodeStatus{"migrate": NodeSucceeded, "seed": NodeSucceeded, "warmup": NodeSucceeded},
+			wantBefore: [][2]string{{"migrate", "seed"}, {"seed", "warmup"}},
+		},
+		{
+			name: "skips dependants of failed nodes",
+			graph: func(record func(string) Effector) *Graph {
+				return NewGraph().
+					Add("migrate", fail).
+					Add("seed", record("seed"), "migrate").
+					Add("warmup", record("warmup"), "seed").
+					Add("metrics", record("metrics"))
+			},
+			wantStatus: map[string]NodeStatus{"migrate": NodeFailed, "seed": NodeSkipped, "warmup": NodeSkipped, "metrics": NodeSucceeded},
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var order []string
+			record := func(name string) Effector {
+				return func(context.Context) error {
+					mu.Lock()
+					defer mu.Unlock()
+					order = append(order, name)
+					return nil
+				}
+			}
+
+			report, err := tt.graph(record).Run(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Graph.Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for name, want := range tt.wantStatus {
+				if got := report[name].Status; got != want {
+					t.Errorf("Graph.Run() status of %q = %v, want %v", name, got, want)
+				}
+			}
+			for _, pair := range tt.wantBefore {
+				if slices.Index(order, pair[0]) > slices.Index(order, pair[1]) {
+					t.Errorf("Graph.Run() order = %v, want %q before %q", order, pair[0], pair[1])
+				}
+			}
+		})
+	}
+}
+
+func TestGraph_Run_Errors(t *testing.T) {
+	errTest := errors.New("test error")
+	report, err := NewGraph().
+		Add("migrate", func(context.Context) error { return errTest }).
+		Add("seed", noopEffector, "migrate").
+		Run(context.Background())
+
+	var stepErr *ErrStep
+	if !errors.As(err, &stepErr) || stepErr.Step != "migrate" || !errors.Is(err, errTest) {
+		t.Errorf("Graph.Run() error = %v, want failure of migrate", err)
+	}
+	var depErr ErrDependencyFailed
+	if !errors.As(report["seed"].Err, &depErr) || depErr.Dependency != "migrate" {
+		t.Errorf("Graph.Run() error of seed = %v, want %v", report["seed"].Err, ErrDependencyFailed{Dependency: "migrate"})
+	}
+
+	if _, err := NewGraph().Add("a", noopEffector, "a").Run(context.Background()); !errors.As(err, &ErrCycle{}) {
+		t.Errorf("Graph.Run() error = %v, want %T", err, ErrCycle{})
+	}
+}
+
+func TestGraph_Run_Limit(t *testing.T) {
+	var running, peak atomic.Int32
+	node := func(context.Context) error {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	}
+
+	g := NewGraph().SetLimit(2)
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		g.Add(name, node)
+	}
+	if _, err := g.Run(context.Background()); err != nil {
+		t.Fatalf("Graph.Run() error = %v", err)
+	}
+	if got := peak.Load(); got != 2 {
+		t.Errorf("Graph.Run() concurrent nodes = %d, want 2", got)
+	}
+}
+
+func TestGraph_Run_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	report, err := NewGraph().
+		Add("first", func(context.Context) error {
+			cancel()
+			return nil
+		}).
+		Add("second", noopEffector, "first").
+		Run(ctx)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Graph.Run() error = %v, want %v", err, context.Canceled)
+	}
+	if got := report["second"].Status; got != NodeSkipped {
+		t.Errorf("Graph.Run() status of second = %v, want %v", got, NodeSkipped)
+	}
+}
+
+func TestGraph_Run_Clock(t *testing.T) {
+	clock := &steppingClock{step: time.Minute}
+	report, err := NewGraph(WithClock(clock)).Add("a", noopEffector).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Graph.Run() error = %v", err)
+	}
+	if got := report["a"].Duration; got != time.Minute {
+		t.Errorf("Graph.Run() duration of a = %v, want %v", got, time.Minute)
+	}
+}
+
+// steppingClock is a [Clock] whose time moves forward by the step every time it is read.
+type steppingClock struct {
+	realClock
+	mu   sync.Mutex
+	now  time.Time
+	step time.Duration
+}
+
+// Now returns the current time and advances the clock by the step.
+func (c *steppingClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now
+	c.now = c.now.Add(c.step)
+	return now
+}
@@ -0,0 +1,69 @@
+package executors
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Hedge returns an effector that starts a duplicate call of the effector whenever no call has succeeded after the given delay,
+// up to maxHedges additional calls. A failed call immediately starts the next hedge.
+//
+// The first successful call wins and the context passed to all other calls is canceled.
+// If all calls fail, all errors are returned as wrapped [errors.Join] error. A [Permanent] error is returned immediately.
+// A panicking call fails with a [*PanicError].
+//
+// The effector must be safe to call concurrently and should be idempotent, e.g. a read against replicated backends.
+func Hedge(delay time.Duration, maxHedges int, effector Effector, opts ...Option) Effector {
+	if effector == nil {
+		return noopEffector
+	}
+	maxHedges = max(maxHedges, 0)
+	o := newOptions(opts)
+
+	return func(ctx context.Context) error {
+		hctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		// The channel is buffered for all calls, so that losing calls don't block after the winner returned.
+		results := make(chan error, maxHedges+1)
+		launched, pending := 0, 0
+		launch := func() {
+			launched++
+			pending++
+			go func() { results <- Protector(effector)(hctx) }()
+		}
+
+		launch()
+		timer := o.clock.NewTimer(delay)
+		defer timer.Stop()
+
+		var errs []error
+		for {
+			select {
+			case err := <-results:
+				pending--
+				if err == nil {
+					return nil
+				}
+				if IsPermanent(err) {
+					return err
+				}
+				errs = append(errs, err)
+				if launched <= maxHedges {
+					launch()
+					timer.Reset(delay)
+				} else if pending == 0 {
+					return errors.Join(errs...)
+				}
+			case <-timer.C():
+				if launched <= maxHedges {
+					launch()
+					timer.Reset(delay)
+				}
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+}
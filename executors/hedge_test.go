@@ -0,0 +1,140 @@
+package executors
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHedge(t *testing.T) {
+	errFailed := errors.New("failed")
+	tests := []struct {
+		name      string
+		delay     time.Duration
+		maxHedges int
+		// attempts returns the duration and error of the call with the given index.
+		attempts  func(i int32) (time.Duration, error)
+		wantErr   bool
+		wantCalls int32
+		wantMax   time.Duration
+	}{
+		{
+			name:      "fast first call doesn't hedge",
+			delay:     50 * time.Millisecond,
+			maxHedges: 2,
+			attempts:  func(i int32) (time.Duration, error) { return 0, nil },
+			wantCalls: 1,
+		},
+		{
+			name:      "slow first call is hedged",
+			delay:     10 * time.Millisecond,
+			maxHedges: 1,
+			attempts: func(i int32) (time.Duration, error) {
+				if i == 0 {
+					return time.Second, nil
+				}
+				return 0, nil
+			},
+			wantCalls: 2,
+			wantMax:   500 * time.Millisecond,
+		},
+		{
+			name:      "failed call starts hedge immediately",
+			delay:     time.Second,
+			maxHedges: 1,
+			attempts: func(i int32) (time.Duration, error) {
+				if i == 0 {
+					return 0, errFailed
+				}
+				return 0, nil
+			},
+			wantCalls: 2,
+			wantMax:   500 * time.Millisecond,
+		},
+		{
+			name:      "all calls fail",
+			delay:     time.Millisecond,
+			maxHedges: 2,
+			attempts:  func(i int32) (time.Duration, error) { return 0, errFailed },
+			wantErr:   true,
+			wantCalls: 3,
+		},
+		{
+			name:      "permanent error is not hedged",
+			delay:     time.Millisecond,
+			maxHedges: 2,
+			attempts:  func(i int32) (time.Duration, error) { return 0, Permanent(errFailed) },
+			wantErr:   true,
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			effector := Effector(func(ctx context.Context) error {
+				d, err := tt.attempts(calls.Add(1) - 1)
+				select {
+				case <-time.After(d):
+					return err
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}).WithHedge(tt.delay, tt.maxHedges)
+
+			start := time.Now()
+			err := effector.Do()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Hedge() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("Hedge() calls = %d, want %d", got, tt.wantCalls)
+			}
+			if tt.wantMax > 0 && time.Since(start) > tt.wantMax {
+				t.Errorf("Hedge() took %v, want at most %v", time.Since(start), tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestHedge_CancelsLosers(t *testing.T) {
+	canceled := make(chan struct{})
+	var calls atomic.Int32
+	effector := Hedge(5*time.Millisecond, 1, func(ctx context.Context) error {
+		if calls.Add(1) == 2 {
+			return nil
+		}
+		<-ctx.Done()
+		close(canceled)
+		return ctx.Err()
+	})
+
+	if err := effector.Do(); err != nil {
+		t.Fatalf("Hedge() error = %v", err)
+	}
+
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("Hedge() did not cancel the losing call")
+	}
+}
+
+func TestHedge_Context_Cancel(t *testing.T) {
+	effector := Hedge(time.Millisecond, 1, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := effector.Do(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Hedge() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	if err := Hedge(time.Millisecond, 1, nil).Do(); err != nil {
+		t.Errorf("Hedge() with nil effector error = %v, want nil", err)
+	}
+}
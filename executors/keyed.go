@@ -0,0 +1,197 @@
+package executors
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultMaxKeys is the default maximum number of keys of the keyed policies.
+	defaultMaxKeys = 10000
+	// defaultIdleTimeout is the default duration after which an unused key of the keyed policies is evicted.
+	defaultIdleTimeout = 10 * time.Minute
+)
+
+// KeyFunc derives the key of a call from its context, e.g. the tenant or the host of a request.
+type KeyFunc func(ctx context.Context) string
+
+// WithMaxKeys is an [Option] that limits the number of keys a keyed policy like [KeyedRateLimiter] keeps state for.
+// If the limit is reached, the least recently used key is evicted. Defaults to 10000.
+func WithMaxKeys(n int) Option {
+	return func(opts *options) {
+		if n > 0 {
+			opts.maxKeys = n
+		}
+	}
+}
+
+// WithIdleTimeout is an [Option] that evicts the state of a key of a keyed policy like [KeyedRateLimiter]
+// if it wasn't used for the given duration. Defaults to 10 minutes.
+func WithIdleTimeout(d time.Duration) Option {
+	return func(opts *options) {
+		if d > 0 {
+			opts.idleTimeout = d
+		}
+	}
+}
+
+// KeyedRateLimiter runs the effector with the specified rate limit per key.
+// Every key gets its own [TokenBucket] with a burst of 1, so one key can't use up the limit of the others.
+func KeyedRateLimiter(keyFunc KeyFunc, r RateLimit, effector Effector, opts ...Option) Effector {
+	if effector == nil {
+		return noopEffector
+	}
+	if r <= 0 {
+		return func(_ context.Context) error {
+			return ErrInvalidRateLimit{}
+		}
+	}
+	return KeyedLimited(keyFunc, func(string) Limiter { return NewTokenBucket(r, 1, opts...) }, effector, opts...)
+}
+
+// KeyedLimited runs the effector once the [Limiter] of its key permits it.
+// The limiter of a key is created with newLimiter on the first call with that key.
+func KeyedLimited(keyFunc KeyFunc, newLimiter func(key string) Limiter, effector Effector, opts ...Option) Effector {
+	if effector == nil {
+		return noopEffector
+	}
+	if newLimiter == nil {
+		return func(_ context.Context) error {
+			return ErrInvalidRateLimit{}
+		}
+	}
+
+	keyFunc = keyFuncOrConstant(keyFunc)
+	// The effector is wrapped once per key, so a call only looks up the wrapped effector of its key.
+	effectors := newKeyed(func(key string) Effector {
+		return Limited(newLimiter(key), effector, opts...)
+	}, newOptions(opts))
+	return func(ctx context.Context) error {
+		return effectors.get(keyFunc(ctx))(ctx)
+	}
+}
+
+// KeyedCircuitBreaker runs the effector with a circuit breaker per key.
+// Every key gets its own [Breaker], so the failures of one key don't open the circuit for the others.
+func KeyedCircuitBreaker(keyFunc KeyFunc, maxFailures int, resetTimeout time.Duration, effector Effector, opts ...Option) Effector {
+	o := newOptions(opts)
+	return KeyedBreaker(keyFunc, func(string) *Breaker {
+		return NewBreaker(BreakerOptions{
+			MaxFailures:  maxFailures,
+			ResetTimeout: resetTimeout,
+			Observer:     o.observer,
+			Clock:        o.clock,
+		})
+	}, effector, opts...)
+}
+
+// KeyedBreaker runs the effector through the [Breaker] of its key.
+// The breaker of a key is created with newBreaker on the first call with that key.
+// Every breaker needs its own [TripPolicy], so newBreaker must not share one between breakers.
+func KeyedBreaker(keyFunc KeyFunc, newBreaker func(key string) *Breaker, effector Effector, opts ...Option) Effector {
+	if effector == nil {
+		return noopEffector
+	}
+	if newBreaker == nil {
+		newBreaker = func(string) *Breaker { return NewBreaker(BreakerOptions{}) }
+	}
+
+	keyFunc = keyFuncOrConstant(keyFunc)
+	// The effector is wrapped once per key, so a call only looks up the wrapped effector of its key.
+	effectors := newKeyed(func(key string) Effector {
+		return newBreaker(key).Wrap(effector)
+	}, newOptions(opts))
+	return func(ctx context.Context) error {
+		return effectors.get(keyFunc(ctx))(ctx)
+	}
+}
+
+// keyFuncOrConstant returns the key func or a key func that returns the same key for all calls if it is nil.
+func keyFuncOrConstant(keyFunc KeyFunc) KeyFunc {
+	if keyFunc == nil {
+		return func(context.Context) string { return "" }
+	}
+	return keyFunc
+}
+
+// keyed holds a value per key and evicts the least recently used and idle keys.
+type keyed[V any] struct {
+	// newValue creates the value of a new key.
+	newValue func(key string) V
+	// maxKeys is the maximum number of keys.
+	maxKeys int
+	// idleTimeout is the duration after which an unused key is evicted.
+	idleTimeout time.Duration
+	// clock is the source of time of the idle timeout.
+	clock Clock
+
+	// mu protects the entries.
+	mu sync.Mutex
+	// order are the entries from the most to the least recently used.
+	order *list.List
+	// entries are the elements of the order by key.
+	entries map[string]*list.Element
+}
+
+// keyedEntry is an entry of a [keyed].
+type keyedEntry[V any] struct {
+	// key is the key of the entry.
+	key string
+	// value is the value of the key.
+	value V
+	// lastUsed is the time the key was used the last time.
+	lastUsed time.Time
+}
+
+// newKeyed creates a new [keyed] with the limits of the options.
+func newKeyed[V any](newValue func(key string) V, o options) *keyed[V] {
+	return &keyed[V]{
+		newValue:    newValue,
+		maxKeys:     o.maxKeys,
+		idleTimeout: o.idleTimeout,
+		clock:       o.clock,
+		order:       list.New(),
+		entries:     map[string]*list.Element{},
+	}
+}
+
+// get returns the value of the key and creates it if the key is new.
+func (k *keyed[V]) get(key string) V {
+	now := k.clock.Now()
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	// The least recently used entries are at the back, so idle entries are evicted from there.
+	for e := k.order.Back(); e != nil && now.Sub(e.Value.(*keyedEntry[V]).lastUsed) > k.idleTimeout; e = k.order.Back() {
+		k.remove(e)
+	}
+
+	if e, ok := k.entries[key]; ok {
+		entry := e.Value.(*keyedEntry[V])
+		entry.lastUsed = now
+		k.order.MoveToFront(e)
+		return entry.value
+	}
+
+	entry := &keyedEntry[V]{key: key, value: k.newValue(key), lastUsed: now}
+	k.entries[key] = k.order.PushFront(entry)
+	if k.order.Len() > k.maxKeys {
+		k.remove(k.order.Back())
+	}
+	return entry.value
+}
+
+// remove removes the element from the order and the entries.
+func (k *keyed[V]) remove(e *list.Element) {
+	k.order.Remove(e)
+	delete(k.entries, e.Value.(*keyedEntry[V]).key)
+}
+
+// len returns the number of keys.
+func (k *keyed[V]) len() int {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.order.Len()
+}
@@ -0,0 +1,139 @@
+package executors
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// tenantKey is the context key of the tenant in the tests.
+type tenantKey struct{}
+
+// withTenant returns a context with the given tenant.
+func withTenant(tenant string) context.Context {
+	return context.WithValue(context.Background(), tenantKey{}, tenant)
+}
+
+// tenantOf is a [KeyFunc] that returns the tenant of the context.
+func tenantOf(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	return tenant
+}
+
+func TestKeyedCircuitBreaker(t *testing.T) {
+	errTest := errors.New("test error")
+	effector := KeyedCircuitBreaker(tenantOf, 1, time.Minute, func(ctx context.Context) error {
+		if tenantOf(ctx) == "noisy" {
+			return errTest
+		}
+		return nil
+	})
+
+	tests := []struct {
+		name    string
+		tenant  string
+		wantErr error
+	}{
+		{name: "noisy tenant trips its circuit", tenant: "noisy", wantErr: ErrCircuitOpen{}},
+		{name: "noisy tenant is rejected", tenant: "noisy", wantErr: ErrCircuitOpen{}},
+		{name: "other tenant is not affected", tenant: "quiet"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := effector.Do(withTenant(tt.tenant))
+			if !errors.Is(err, tt.wantErr) || (err != nil) != (tt.wantErr != nil) {
+				t.Errorf("KeyedCircuitBreaker() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestKeyedRateLimiter(t *testing.T) {
+	effector := KeyedRateLimiter(tenantOf, 0.1, noopEffector)
+
+	tests := []struct {
+		name    string
+		tenant  string
+		wantErr error
+	}{
+		{name: "first call of tenant", tenant: "noisy"},
+		{name: "second call of tenant is limited", tenant: "noisy", wantErr: errWaitExceedsDeadline},
+		{name: "other tenant is not affected", tenant: "quiet"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(withTenant(tt.tenant), 100*time.Millisecond)
+			defer cancel()
+
+			err := effector.Do(ctx)
+			if !errors.Is(err, tt.wantErr) || (err != nil) != (tt.wantErr != nil) {
+				t.Errorf("KeyedRateLimiter() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := KeyedRateLimiter(tenantOf, 0, noopEffector).Do(); !errors.Is(err, ErrInvalidRateLimit{}) {
+		t.Errorf("KeyedRateLimiter() error = %v, want %v", err, ErrInvalidRateLimit{})
+	}
+}
+
+func Test_keyed_get(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		keys     []string
+		sleep    time.Duration
+		wantLen  int
+		wantKept string
+	}{
+		{
+			name:     "reuses values",
+			keys:     []string{"a", "a", "b"},
+			wantLen:  2,
+			wantKept: "a",
+		},
+		{
+			name:     "evicts least recently used key",
+			opts:     []Option{WithMaxKeys(2)},
+			keys:     []string{"a", "b", "a", "c"},
+			wantLen:  2,
+			wantKept: "a",
+		},
+		{
+			name:     "evicts idle keys",
+			opts:     []Option{WithIdleTimeout(10 * time.Millisecond)},
+			keys:     []string{"a", "b"},
+			sleep:    20 * time.Millisecond,
+			wantLen:  1,
+			wantKept: "c",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created := map[string]int{}
+			k := newKeyed(func(key string) string {
+				created[key]++
+				return key
+			}, newOptions(tt.opts))
+
+			for _, key := range tt.keys {
+				if got := k.get(key); got != key {
+					t.Errorf("keyed.get() = %q, want %q", got, key)
+				}
+			}
+			time.Sleep(tt.sleep)
+			k.get(tt.wantKept)
+
+			if got := k.len(); got != tt.wantLen {
+				t.Errorf("keyed.len() = %d, want %d", got, tt.wantLen)
+			}
+			if created[tt.wantKept] != 1 {
+				t.Errorf("keyed.get() created %q %d times, want 1", tt.wantKept, created[tt.wantKept])
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package executors
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Limiter decides when a call may proceed. It is the backend of [Limited] and [RateLimiter].
+// Share a limiter between multiple effectors to let them share one quota.
+//
+// Implementations must be safe for concurrent use.
+type Limiter interface {
+	// Reserve reserves a permit for one call and returns the reservation.
+	// The call may proceed once the delay of the reservation has passed.
+	Reserve(ctx context.Context) (Reservation, error)
+}
+
+// Reservation is a permit reserved by a [Limiter].
+type Reservation struct {
+	// Delay is the duration the call has to wait before it may proceed.
+	Delay time.Duration
+	// Cancel gives the permit back if the call doesn't proceed.
+	// It may be nil if the limiter can't give permits back.
+	Cancel func()
+}
+
+// cancel gives the permit back if the limiter supports it.
+func (r Reservation) cancel() {
+	if r.Cancel != nil {
+		r.Cancel()
+	}
+}
+
+var _ Limiter = (*TokenBucket)(nil)
+
+// TokenBucket is an in-memory [Limiter] that allows bursts of up to burst calls
+// and refills at the given rate.
+type TokenBucket struct {
+	// limiter is the underlying token bucket.
+	limiter *rate.Limiter
+	// clock is the source of time of the bucket.
+	clock Clock
+}
+
+// NewTokenBucket creates a new [TokenBucket] with the given rate and burst.
+// A burst smaller than 1 is treated as 1. Use [WithClock] to set its clock.
+func NewTokenBucket(r RateLimit, burst int, opts ...Option) *TokenBucket {
+	return &TokenBucket{limiter: rate.NewLimiter(r, max(burst, 1)), clock: newOptions(opts).clock}
+}
+
+// Reserve reserves a token. Returns [ErrInvalidRateLimit] if the rate is not positive.
+func (t *TokenBucket) Reserve(_ context.Context) (Reservation, error) {
+	if t.limiter.Limit() <= 0 {
+		return Reservation{}, ErrInvalidRateLimit{}
+	}
+
+	now := t.clock.Now()
+	r := t.limiter.ReserveN(now, 1)
+	if !r.OK() {
+		return Reservation{}, ErrInvalidRateLimit{}
+	}
+	return Reservation{Delay: r.DelayFrom(now), Cancel: func() { r.CancelAt(t.clock.Now()) }}, nil
+}
+
+var _ Limiter = (*SlidingWindowLog)(nil)
+
+// SlidingWindowLog is an in-memory [Limiter] that allows at most limit calls in any window of the given length.
+// Unlike a [TokenBucket], it doesn't allow more calls than the limit at the border of two windows,
+// at the cost of remembering the time of every call in the window.
+type SlidingWindowLog struct {
+	// limit is the maximum number of calls in a window.
+	limit int
+	// window is the length of the window.
+	window time.Duration
+	// clock is the source of time of the log.
+	clock Clock
+
+	// mu protects the log.
+	mu sync.Mutex
+	// log are the sorted times at which the reserved calls may proceed.
+	log []time.Time
+}
+
+// NewSlidingWindowLog creates a new [SlidingWindowLog] that allows limit calls per window.
+// Use [WithClock] to set its clock.
+func NewSlidingWindowLog(limit int, window time.Duration, opts ...Option) *SlidingWindowLog {
+	return &SlidingWindowLog{limit: limit, window: window, clock: newOptions(opts).clock}
+}
+
+// Reserve reserves a slot in the window. Returns [ErrInvalidRateLimit] if the limit or the window is not positive.
+func (s *SlidingWindowLog) Reserve(_ context.Context) (Reservation, error) {
+	if s.limit < 1 || s.window <= 0 {
+		return Reservation{}, ErrInvalidRateLimit{}
+	}
+
+	now := s.clock.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	start := now.Add(-s.window)
+	expired := 0
+	for expired < len(s.log) && !s.log[expired].After(start) {
+		expired++
+	}
+	s.log = slices.Delete(s.log, 0, expired)
+
+	at := now
+	if len(s.log) >= s.limit {
+		// The call may proceed once the limit-th latest call has left the window.
+		at = s.log[len(s.log)-s.limit].Add(s.window)
+	}
+	s.log = append(s.log, at)
+
+	return Reservation{
+		Delay: at.Sub(now),
+		Cancel: func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if i := slices.Index(s.log, at); i >= 0 {
+				s.log = slices.Delete(s.log, i, i+1)
+			}
+		},
+	}, nil
+}
+
+// RemoteStore is the backend of a [RemoteLimiter], e.g. a Redis or database client.
+// All replicas using the same store and key share one quota.
+type RemoteStore interface {
+	// Reserve atomically records a call under the key in a sliding window of the given length
+	// that allows limit calls and returns the delay after which the call may proceed.
+	Reserve(ctx context.Context, key string, limit int, window time.Duration) (time.Duration, error)
+}
+
+var _ Limiter = (*RemoteLimiter)(nil)
+
+// RemoteLimiter is a [Limiter] that keeps its state in a [RemoteStore],
+// so multiple processes can share one quota.
+// Its reservations can't be canceled.
+type RemoteLimiter struct {
+	// store holds the state of the limiter.
+	store RemoteStore
+	// key identifies the quota in the store.
+	key string
+	// limit is the maximum number of calls in a window.
+	limit int
+	// window is the length of the window.
+	window time.Duration
+}
+
+// NewRemoteLimiter creates a new [RemoteLimiter] that allows limit calls per window for the key in the store.
+func NewRemoteLimiter(store RemoteStore, key string, limit int, window time.Duration) *RemoteLimiter {
+	return &RemoteLimiter{store: store, key: key, limit: limit, window: window}
+}
+
+// Reserve reserves a slot in the store. Returns [ErrInvalidRateLimit] if the store is nil or the limit or the window is not positive.
+func (r *RemoteLimiter) Reserve(ctx context.Context) (Reservation, error) {
+	if r.store == nil || r.limit < 1 || r.window <= 0 {
+		return Reservation{}, ErrInvalidRateLimit{}
+	}
+
+	delay, err := r.store.Reserve(ctx, r.key, r.limit, r.window)
+	if err != nil {
+		return Reservation{}, err
+	}
+	return Reservation{Delay: delay}, nil
+}
@@ -0,0 +1,270 @@
+package executors
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTokenBucket_Reserve(t *testing.T) {
+	tests := []struct {
+		name        string
+		rate        RateLimit
+		burst       int
+		calls       int
+		wantDelayed int
+		wantErr     bool
+	}{
+		{
+			name:        "burst of one",
+			rate:        1,
+			burst:       1,
+			calls:       3,
+			wantDelayed: 2,
+		},
+		{
+			name:        "burst covers all calls",
+			rate:        1,
+			burst:       3,
+			calls:       3,
+			wantDelayed: 0,
+		},
+		{
+			name:        "zero burst is treated as one",
+			rate:        1,
+			burst:       0,
+			calls:       2,
+			wantDelayed: 1,
+		},
+		{
+			name:    "invalid rate",
+			rate:    0,
+			burst:   1,
+			calls:   1,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bucket := NewTokenBucket(tt.rate, tt.burst)
+			delayed := 0
+			for range tt.calls {
+				r, err := bucket.Reserve(context.Background())
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("TokenBucket.Reserve() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if r.Delay > 0 {
+					delayed++
+				}
+			}
+			if delayed != tt.wantDelayed {
+				t.Errorf("TokenBucket.Reserve() delayed = %d, want %d", delayed, tt.wantDelayed)
+			}
+		})
+	}
+}
+
+func TestSlidingWindowLog_Reserve(t *testing.T) {
+	tests := []struct {
+		name       string
+		limit      int
+		window     time.Duration
+		calls      int
+		wantDelays []time.Duration
+		wantErr    bool
+	}{
+		{
+			name:       "within limit",
+			limit:      3,
+			window:     time.Minute,
+			calls:      3,
+			wantDelays: []time.Duration{0, 0, 0},
+		},
+		{
+			name:       "exceeding limit",
+			limit:      2,
+			window:     time.Minute,
+			calls:      5,
+			wantDelays: []time.Duration{0, 0, time.Minute, time.Minute, 2 * time.Minute},
+		},
+		{
+			name:    "invalid limit",
+			limit:   0,
+			window:  time.Minute,
+			calls:   1,
+			wantErr: true,
+		},
+		{
+			name:    "invalid window",
+			limit:   1,
+			window:  0,
+			calls:   1,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := NewSlidingWindowLog(tt.limit, tt.window)
+			for i := range tt.calls {
+				r, err := log.Reserve(context.Background())
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("SlidingWindowLog.Reserve() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if tt.wantErr {
+					continue
+				}
+				// The delays are measured from slightly different points in time.
+				if diff := tt.wantDelays[i] - r.Delay; diff < 0 || diff > 100*time.Millisecond {
+					t.Errorf("SlidingWindowLog.Reserve() call %d delay = %v, want %v", i, r.Delay, tt.wantDelays[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSlidingWindowLog_Cancel(t *testing.T) {
+	log := NewSlidingWindowLog(1, time.Minute)
+	first, err := log.Reserve(context.Background())
+	if err != nil {
+		t.Fatalf("SlidingWindowLog.Reserve() error = %v", err)
+	}
+	first.Cancel()
+
+	second, err := log.Reserve(context.Background())
+	if err != nil {
+		t.Fatalf("SlidingWindowLog.Reserve() error = %v", err)
+	}
+	if second.Delay != 0 {
+		t.Errorf("SlidingWindowLog.Reserve() delay = %v, want 0 after cancel", second.Delay)
+	}
+}
+
+// fakeStore is a [RemoteStore] that keeps a [SlidingWindowLog] per key in memory.
+type fakeStore struct {
+	mu   sync.Mutex
+	logs map[string]*SlidingWindowLog
+	err  error
+}
+
+func (s *fakeStore) Reserve(ctx context.Context, key string, limit int, window time.Duration) (time.Duration, error) {
+	s.mu.Lock()
+	if s.err != nil {
+		s.mu.Unlock()
+		return 0, s.err
+	}
+	if s.logs == nil {
+		s.logs = map[string]*SlidingWindowLog{}
+	}
+	log, ok := s.logs[key]
+	if !ok {
+		log = NewSlidingWindowLog(limit, window)
+		s.logs[key] = log
+	}
+	s.mu.Unlock()
+
+	r, err := log.Reserve(ctx)
+	return r.Delay, err
+}
+
+func TestRemoteLimiter_Reserve(t *testing.T) {
+	errStore := errors.New("store unavailable")
+	tests := []struct {
+		name        string
+		store       *fakeStore
+		keys        []string
+		wantDelayed int
+		wantErr     error
+	}{
+		{
+			name:        "replicas share one quota",
+			store:       &fakeStore{},
+			keys:        []string{"api", "api", "api"},
+			wantDelayed: 1,
+		},
+		{
+			name:        "keys have separate quotas",
+			store:       &fakeStore{},
+			keys:        []string{"a", "b", "c"},
+			wantDelayed: 0,
+		},
+		{
+			name:    "store error",
+			store:   &fakeStore{err: errStore},
+			keys:    []string{"api"},
+			wantErr: errStore,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delayed := 0
+			for _, key := range tt.keys {
+				// Every replica creates its own limiter on the shared store.
+				limiter := NewRemoteLimiter(tt.store, key, 2, time.Minute)
+				r, err := limiter.Reserve(context.Background())
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("RemoteLimiter.Reserve() error = %v, want %v", err, tt.wantErr)
+				}
+				if r.Delay > 0 {
+					delayed++
+				}
+			}
+			if delayed != tt.wantDelayed {
+				t.Errorf("RemoteLimiter.Reserve() delayed = %d, want %d", delayed, tt.wantDelayed)
+			}
+		})
+	}
+}
+
+func TestLimited(t *testing.T) {
+	tests := []struct {
+		name    string
+		limiter Limiter
+		ctx     func() (context.Context, context.CancelFunc)
+		wantErr error
+	}{
+		{
+			name:    "permitted",
+			limiter: NewTokenBucket(10, 2),
+			ctx:     func() (context.Context, context.CancelFunc) { return context.Background(), func() {} },
+		},
+		{
+			name:    "nil limiter",
+			limiter: nil,
+			ctx:     func() (context.Context, context.CancelFunc) { return context.Background(), func() {} },
+			wantErr: ErrInvalidRateLimit{},
+		},
+		{
+			name:    "wait exceeds deadline",
+			limiter: NewSlidingWindowLog(1, time.Minute),
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), time.Second)
+			},
+			wantErr: errWaitExceedsDeadline,
+		},
+		{
+			name:    "remote limiter error",
+			limiter: NewRemoteLimiter(nil, "api", 1, time.Minute),
+			ctx:     func() (context.Context, context.CancelFunc) { return context.Background(), func() {} },
+			wantErr: ErrInvalidRateLimit{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			effector := Limited(tt.limiter, noopEffector)
+			// The second call exhausts limiters with a single permit.
+			err := errors.Join(effector(ctx), effector(ctx))
+			if !errors.Is(err, tt.wantErr) || (err != nil) != (tt.wantErr != nil) {
+				t.Errorf("Limited() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package executors
+
+import (
+	"context"
+	"time"
+)
+
+// Observer receives the events emitted by the policies of this package.
+// It can be used to log retries, count circuit breaker trips or alert on rate limit waits.
+//
+// Implementations must be safe for concurrent use and should return quickly, as they are called synchronously.
+// Embed [NoopObserver] to only implement the events you are interested in.
+type Observer interface {
+	// OnRetry is called before the given attempt is retried after the given delay.
+	// The attempt starts at 1 for the first call of the effector.
+	OnRetry(ctx context.Context, attempt int, err error, delay time.Duration)
+	// OnStateChange is called when a circuit breaker changes its state.
+	OnStateChange(ctx context.Context, from, to State)
+	// OnRateLimited is called when a call has to wait for the given duration because of a rate limit.
+	OnRateLimited(ctx context.Context, wait time.Duration)
+	// OnTimeout is called when a call is aborted because it exceeded the given timeout.
+	OnTimeout(ctx context.Context, timeout time.Duration)
+	// OnPanic is called when a panic is recovered with the recovered value and the stack trace of the panicking goroutine.
+	OnPanic(ctx context.Context, value any, stack []byte)
+	// OnRejected is called when a call is rejected with the given error without running the effector,
+	// e.g. because a bulkhead is full.
+	OnRejected(ctx context.Context, err error)
+}
+
+var _ Observer = NoopObserver{}
+
+// NoopObserver is an [Observer] that ignores all events.
+type NoopObserver struct{}
+
+// OnRetry does nothing.
+func (NoopObserver) OnRetry(context.Context, int, error, time.Duration) {}
+
+// OnStateChange does nothing.
+func (NoopObserver) OnStateChange(context.Context, State, State) {}
+
+// OnRateLimited does nothing.
+func (NoopObserver) OnRateLimited(context.Context, time.Duration) {}
+
+// OnTimeout does nothing.
+func (NoopObserver) OnTimeout(context.Context, time.Duration) {}
+
+// OnPanic does nothing.
+func (NoopObserver) OnPanic(context.Context, any, []byte) {}
+
+// OnRejected does nothing.
+func (NoopObserver) OnRejected(context.Context, error) {}
+
+// Observers returns an [Observer] that forwards all events to the given observers in order.
+func Observers(observers ...Observer) Observer {
+	return multiObserver(observers)
+}
+
+// multiObserver is an [Observer] that forwards all events to multiple observers.
+type multiObserver []Observer
+
+// OnRetry forwards the event to all observers.
+func (m multiObserver) OnRetry(ctx context.Context, attempt int, err error, delay time.Duration) {
+	for _, o := range m {
+		o.OnRetry(ctx, attempt, err, delay)
+	}
+}
+
+// OnStateChange forwards the event to all observers.
+func (m multiObserver) OnStateChange(ctx context.Context, from, to State) {
+	for _, o := range m {
+		o.OnStateChange(ctx, from, to)
+	}
+}
+
+// OnRateLimited forwards the event to all observers.
+func (m multiObserver) OnRateLimited(ctx context.Context, wait time.Duration) {
+	for _, o := range m {
+		o.OnRateLimited(ctx, wait)
+	}
+}
+
+// OnTimeout forwards the event to all observers.
+func (m multiObserver) OnTimeout(ctx context.Context, timeout time.Duration) {
+	for _, o := range m {
+		o.OnTimeout(ctx, timeout)
+	}
+}
+
+// OnPanic forwards the event to all observers.
+func (m multiObserver) OnPanic(ctx context.Context, value any, stack []byte) {
+	for _, o := range m {
+		o.OnPanic(ctx, value, stack)
+	}
+}
+
+// OnRejected forwards the event to all observers.
+func (m multiObserver) OnRejected(ctx context.Context, err error) {
+	for _, o := range m {
+		o.OnRejected(ctx, err)
+	}
+}
+
+// observerOrNoop returns the observer or a [NoopObserver] if it is nil.
+func observerOrNoop(o Observer) Observer {
+	if o == nil {
+		return NoopObserver{}
+	}
+	return o
+}
+
+// Option configures a policy like [RateLimiter], [Timeouter], [Protector], [Bulkhead] or [KeyedCircuitBreaker].
+type Option func(*options)
+
+// options are the settings shared by the policies that accept an [Option].
+type options struct {
+	// observer receives the events emitted by the policy.
+	observer Observer
+	// maxKeys is the maximum number of keys of a keyed policy.
+	maxKeys int
+	// idleTimeout is the duration after which an unused key of a keyed policy is evicted.
+	idleTimeout time.Duration
+	// clock is the source of time of the policy.
+	clock Clock
+}
+
+// newOptions applies the given options on top of the defaults.
+func newOptions(opts []Option) options {
+	o := options{observer: NoopObserver{}, maxKeys: defaultMaxKeys, idleTimeout: defaultIdleTimeout, clock: realClock{}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithObserver is an [Option] that attaches an [Observer] to a policy.
+func WithObserver(o Observer) Option {
+	return func(opts *options) {
+		opts.observer = observerOrNoop(o)
+	}
+}
@@ -0,0 +1,192 @@
+package executors
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// recordingObserver is an [Observer] that records all events.
+type recordingObserver struct {
+	mu          sync.Mutex
+	retries     []time.Duration
+	transitions []transition
+	waits       []time.Duration
+	timeouts    []time.Duration
+	panics      []any
+	rejections  []error
+}
+
+func (r *recordingObserver) OnRetry(_ context.Context, _ int, _ error, delay time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.retries = append(r.retries, delay)
+}
+
+func (r *recordingObserver) OnStateChange(_ context.Context, from, to State) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.transitions = append(r.transitions, transition{from: from, to: to})
+}
+
+func (r *recordingObserver) OnRateLimited(_ context.Context, wait time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.waits = append(r.waits, wait)
+}
+
+func (r *recordingObserver) OnTimeout(_ context.Context, timeout time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.timeouts = append(r.timeouts, timeout)
+}
+
+func (r *recordingObserver) OnPanic(_ context.Context, value any, stack []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(stack) == 0 {
+		panic("empty stack")
+	}
+	r.panics = append(r.panics, value)
+}
+
+func (r *recordingObserver) OnRejected(_ context.Context, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rejections = append(r.rejections, err)
+}
+
+func TestObserver_Events(t *testing.T) {
+	failing := func(ctx context.Context) error { return errors.New("failed") }
+	slow := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	tests := []struct {
+		name  string
+		run   func(o Observer)
+		check func(t *testing.T, r *recordingObserver)
+	}{
+		{
+			name: "retry",
+			run: func(o Observer) {
+				retrier := Retrier{MaxRetries: 3, Backoff: ConstantBackoff(time.Millisecond), Observer: o}
+				_ = Effector(failing).WithRetry(retrier).Do()
+			},
+			check: func(t *testing.T, r *recordingObserver) {
+				if len(r.retries) != 2 || r.retries[0] != time.Millisecond {
+					t.Errorf("OnRetry() delays = %v, want 2 retries with 1ms", r.retries)
+				}
+			},
+		},
+		{
+			name: "state change",
+			run: func(o Observer) {
+				b := NewBreaker(BreakerOptions{MaxFailures: 1, ResetTimeout: 10 * time.Millisecond, Observer: o})
+				_ = b.Wrap(failing).Do()
+				time.Sleep(20 * time.Millisecond)
+				_ = b.Wrap(noopEffector).Do()
+			},
+			check: func(t *testing.T, r *recordingObserver) {
+				want := []transition{{StateClosed, StateOpen}, {StateOpen, StateHalfOpen}, {StateHalfOpen, StateClosed}}
+				if len(r.transitions) != len(want) {
+					t.Fatalf("OnStateChange() transitions = %v, want %v", r.transitions, want)
+				}
+				for i := range want {
+					if r.transitions[i] != want[i] {
+						t.Errorf("OnStateChange() transition %d = %v, want %v", i, r.transitions[i], want[i])
+					}
+				}
+			},
+		},
+		{
+			name: "rate limited",
+			run: func(o Observer) {
+				effector := Effector(noopEffector).WithRateLimit(rate.Every(10*time.Millisecond), WithObserver(o))
+				_ = effector.Do()
+				_ = effector.Do()
+			},
+			check: func(t *testing.T, r *recordingObserver) {
+				if len(r.waits) != 1 || r.waits[0] <= 0 {
+					t.Errorf("OnRateLimited() waits = %v, want 1 positive wait", r.waits)
+				}
+			},
+		},
+		{
+			name: "timeout",
+			run: func(o Observer) {
+				_ = Effector(slow).WithTimeout(time.Millisecond, WithObserver(o)).Do()
+				_ = Effector(noopEffector).WithTimeout(time.Second, WithObserver(o)).Do()
+			},
+			check: func(t *testing.T, r *recordingObserver) {
+				if len(r.timeouts) != 1 || r.timeouts[0] != time.Millisecond {
+					t.Errorf("OnTimeout() timeouts = %v, want [1ms]", r.timeouts)
+				}
+			},
+		},
+		{
+			name: "timeout of parent context is not reported",
+			run: func(o Observer) {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+				defer cancel()
+				_ = Effector(slow).WithTimeout(time.Second, WithObserver(o)).Do(ctx)
+			},
+			check: func(t *testing.T, r *recordingObserver) {
+				if len(r.timeouts) != 0 {
+					t.Errorf("OnTimeout() timeouts = %v, want none", r.timeouts)
+				}
+			},
+		},
+		{
+			name: "panic",
+			run: func(o Observer) {
+				_ = Effector(func(ctx context.Context) error { panic("boom") }).WithProtection(WithObserver(o)).Do()
+			},
+			check: func(t *testing.T, r *recordingObserver) {
+				if len(r.panics) != 1 || r.panics[0] != "boom" {
+					t.Errorf("OnPanic() values = %v, want [boom]", r.panics)
+				}
+			},
+		},
+		{
+			name: "bulkhead rejection",
+			run: func(o Observer) {
+				release := make(chan struct{})
+				started := make(chan struct{})
+				effector := Bulkhead(1, 0, func(ctx context.Context) error {
+					close(started)
+					<-release
+					return nil
+				}, WithObserver(o))
+				done := make(chan struct{})
+				go func() {
+					defer close(done)
+					_ = effector.Do()
+				}()
+				<-started
+				_ = effector.Do()
+				close(release)
+				<-done
+			},
+			check: func(t *testing.T, r *recordingObserver) {
+				if len(r.rejections) != 1 || !errors.Is(r.rejections[0], ErrBulkheadFull{}) {
+					t.Errorf("OnRejected() errors = %v, want [%v]", r.rejections, ErrBulkheadFull{})
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, second := &recordingObserver{}, &recordingObserver{}
+			tt.run(Observers(first, second))
+			tt.check(t, first)
+			tt.check(t, second)
+		})
+	}
+}
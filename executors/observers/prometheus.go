@@ -0,0 +1,143 @@
+// Package observers provides [executors.Observer] implementations that record the events of the policies
+// as Prometheus metrics or OpenTelemetry span events.
+// It is a module of its own, so the executors module doesn't depend on Prometheus and OpenTelemetry.
+package observers
+
+import (
+	"context"
+	"time"
+
+	"github.com/lvlcn-t/go-kit/executors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	_ executors.Observer   = (*Prometheus)(nil)
+	_ prometheus.Collector = (*Prometheus)(nil)
+)
+
+// Prometheus is an [executors.Observer] that records the events of the policies as Prometheus metrics.
+// It implements [prometheus.Collector] and has to be registered with a registry.
+//
+// Example:
+//
+//	observer := observers.NewPrometheus("myapp", "payments")
+//	registry.MustRegister(observer)
+//	retrier := executors.Retrier{MaxRetries: 3, Observer: observer}
+type Prometheus struct {
+	// retries counts the retries.
+	retries prometheus.Counter
+	// retryDelays observes the delays before retries.
+	retryDelays prometheus.Histogram
+	// stateChanges counts the state changes of circuit breakers.
+	stateChanges *prometheus.CounterVec
+	// rateLimitWaits observes the time calls had to wait because of a rate limit.
+	rateLimitWaits prometheus.Histogram
+	// timeouts counts the calls that exceeded their timeout.
+	timeouts prometheus.Counter
+	// panics counts the recovered panics.
+	panics prometheus.Counter
+	// rejections counts the rejected calls.
+	rejections prometheus.Counter
+}
+
+// NewPrometheus creates a new [Prometheus] observer with metrics in the given namespace and subsystem.
+// Use different subsystems to distinguish the metrics of multiple observers registered with the same registry.
+func NewPrometheus(namespace, subsystem string) *Prometheus {
+	return &Prometheus{
+		retries: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "executor_retries_total",
+			Help:      "Total number of retries.",
+		}),
+		retryDelays: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "executor_retry_delay_seconds",
+			Help:      "Delay before a retry in seconds.",
+			Buckets:   prometheus.DefBuckets,
+		}),
+		stateChanges: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "executor_circuit_breaker_state_changes_total",
+			Help:      "Total number of circuit breaker state changes.",
+		}, []string{"from", "to"}),
+		rateLimitWaits: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "executor_rate_limit_wait_seconds",
+			Help:      "Time a call had to wait because of a rate limit in seconds.",
+			Buckets:   prometheus.DefBuckets,
+		}),
+		timeouts: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "executor_timeouts_total",
+			Help:      "Total number of calls that exceeded their timeout.",
+		}),
+		panics: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "executor_panics_total",
+			Help:      "Total number of recovered panics.",
+		}),
+		rejections: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "executor_rejections_total",
+			Help:      "Total number of calls rejected without running the effector.",
+		}),
+	}
+}
+
+// collectors returns all collectors of the observer.
+func (p *Prometheus) collectors() []prometheus.Collector {
+	return []prometheus.Collector{p.retries, p.retryDelays, p.stateChanges, p.rateLimitWaits, p.timeouts, p.panics, p.rejections}
+}
+
+// Describe sends the descriptors of all metrics to the channel.
+func (p *Prometheus) Describe(ch chan<- *prometheus.Desc) {
+	for _, c := range p.collectors() {
+		c.Describe(ch)
+	}
+}
+
+// Collect sends all metrics to the channel.
+func (p *Prometheus) Collect(ch chan<- prometheus.Metric) {
+	for _, c := range p.collectors() {
+		c.Collect(ch)
+	}
+}
+
+// OnRetry counts the retry and observes its delay.
+func (p *Prometheus) OnRetry(_ context.Context, _ int, _ error, delay time.Duration) {
+	p.retries.Inc()
+	p.retryDelays.Observe(delay.Seconds())
+}
+
+// OnStateChange counts the state change.
+func (p *Prometheus) OnStateChange(_ context.Context, from, to executors.State) {
+	p.stateChanges.WithLabelValues(from.String(), to.String()).Inc()
+}
+
+// OnRateLimited observes the wait time.
+func (p *Prometheus) OnRateLimited(_ context.Context, wait time.Duration) {
+	p.rateLimitWaits.Observe(wait.Seconds())
+}
+
+// OnTimeout counts the timeout.
+func (p *Prometheus) OnTimeout(context.Context, time.Duration) {
+	p.timeouts.Inc()
+}
+
+// OnPanic counts the panic.
+func (p *Prometheus) OnPanic(context.Context, any, []byte) {
+	p.panics.Inc()
+}
+
+// OnRejected counts the rejection.
+func (p *Prometheus) OnRejected(context.Context, error) {
+	p.rejections.Inc()
+}
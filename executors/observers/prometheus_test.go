@@ -0,0 +1,62 @@
+package observers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lvlcn-t/go-kit/executors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestPrometheus(t *testing.T) {
+	observer := NewPrometheus("test", "executor")
+	registry := prometheus.NewRegistry()
+	if err := registry.Register(observer); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	ctx := context.Background()
+	observer.OnRetry(ctx, 1, errors.New("failed"), time.Second)
+	observer.OnRetry(ctx, 2, errors.New("failed"), 2*time.Second)
+	observer.OnStateChange(ctx, executors.StateClosed, executors.StateOpen)
+	observer.OnRateLimited(ctx, time.Millisecond)
+	observer.OnTimeout(ctx, time.Second)
+	observer.OnPanic(ctx, "boom", []byte("stack"))
+	observer.OnRejected(ctx, executors.ErrBulkheadFull{})
+
+	want := `
+# HELP test_executor_executor_circuit_breaker_state_changes_total Total number of circuit breaker state changes.
+# TYPE test_executor_executor_circuit_breaker_state_changes_total counter
+test_executor_executor_circuit_breaker_state_changes_total{from="closed",to="open"} 1
+# HELP test_executor_executor_panics_total Total number of recovered panics.
+# TYPE test_executor_executor_panics_total counter
+test_executor_executor_panics_total 1
+# HELP test_executor_executor_rejections_total Total number of calls rejected without running the effector.
+# TYPE test_executor_executor_rejections_total counter
+test_executor_executor_rejections_total 1
+# HELP test_executor_executor_retries_total Total number of retries.
+# TYPE test_executor_executor_retries_total counter
+test_executor_executor_retries_total 2
+# HELP test_executor_executor_timeouts_total Total number of calls that exceeded their timeout.
+# TYPE test_executor_executor_timeouts_total counter
+test_executor_executor_timeouts_total 1
+`
+	err := testutil.GatherAndCompare(registry, strings.NewReader(want),
+		"test_executor_executor_circuit_breaker_state_changes_total",
+		"test_executor_executor_panics_total",
+		"test_executor_executor_rejections_total",
+		"test_executor_executor_retries_total",
+		"test_executor_executor_timeouts_total",
+	)
+	if err != nil {
+		t.Errorf("GatherAndCompare() error = %v", err)
+	}
+
+	if got := testutil.CollectAndCount(observer); got != 7 {
+		t.Errorf("CollectAndCount() = %d, want 7", got)
+	}
+}
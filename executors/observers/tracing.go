@@ -0,0 +1,72 @@
+package observers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lvlcn-t/go-kit/executors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var _ executors.Observer = Tracing{}
+
+// Tracing is an [executors.Observer] that records the events of the policies as OpenTelemetry span events
+// on the span of the context passed to the effector.
+// Events are dropped if the context doesn't carry a recording span.
+type Tracing struct{}
+
+// OnRetry adds a retry event to the span.
+func (Tracing) OnRetry(ctx context.Context, attempt int, err error, delay time.Duration) {
+	trace.SpanFromContext(ctx).AddEvent("executors.retry", trace.WithAttributes(
+		attribute.Int("executors.attempt", attempt),
+		attribute.String("executors.error", errorString(err)),
+		attribute.String("executors.delay", delay.String()),
+	))
+}
+
+// OnStateChange adds a circuit breaker state change event to the span.
+func (Tracing) OnStateChange(ctx context.Context, from, to executors.State) {
+	trace.SpanFromContext(ctx).AddEvent("executors.circuit_breaker.state_change", trace.WithAttributes(
+		attribute.String("executors.state.from", from.String()),
+		attribute.String("executors.state.to", to.String()),
+	))
+}
+
+// OnRateLimited adds a rate limit event to the span.
+func (Tracing) OnRateLimited(ctx context.Context, wait time.Duration) {
+	trace.SpanFromContext(ctx).AddEvent("executors.rate_limited", trace.WithAttributes(
+		attribute.String("executors.wait", wait.String()),
+	))
+}
+
+// OnTimeout adds a timeout event to the span.
+func (Tracing) OnTimeout(ctx context.Context, timeout time.Duration) {
+	trace.SpanFromContext(ctx).AddEvent("executors.timeout", trace.WithAttributes(
+		attribute.String("executors.timeout", timeout.String()),
+	))
+}
+
+// OnPanic adds a panic event including the stack trace to the span.
+func (Tracing) OnPanic(ctx context.Context, value any, stack []byte) {
+	trace.SpanFromContext(ctx).AddEvent("executors.panic", trace.WithAttributes(
+		attribute.String("executors.panic.value", fmt.Sprint(value)),
+		attribute.String("executors.panic.stack", string(stack)),
+	))
+}
+
+// OnRejected adds a rejection event to the span.
+func (Tracing) OnRejected(ctx context.Context, err error) {
+	trace.SpanFromContext(ctx).AddEvent("executors.rejected", trace.WithAttributes(
+		attribute.String("executors.error", errorString(err)),
+	))
+}
+
+// errorString returns the error message or an empty string if the error is nil.
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,47 @@
+package observers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lvlcn-t/go-kit/executors"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestTracing(t *testing.T) {
+	span := &recordingSpan{}
+	ctx := trace.ContextWithSpan(context.Background(), span)
+
+	observer := Tracing{}
+	observer.OnRetry(ctx, 1, errors.New("failed"), time.Second)
+	observer.OnStateChange(ctx, executors.StateClosed, executors.StateOpen)
+	observer.OnRateLimited(ctx, time.Millisecond)
+	observer.OnTimeout(ctx, time.Second)
+	observer.OnPanic(ctx, "boom", []byte("stack"))
+	observer.OnRejected(ctx, executors.ErrBulkheadFull{})
+
+	want := []string{"executors.retry", "executors.circuit_breaker.state_change", "executors.rate_limited", "executors.timeout", "executors.panic", "executors.rejected"}
+	if len(span.events) != len(want) {
+		t.Fatalf("recorded %d events, want %d", len(span.events), len(want))
+	}
+	for i, name := range want {
+		if span.events[i] != name {
+			t.Errorf("event %d = %q, want %q", i, span.events[i], name)
+		}
+	}
+}
+
+// recordingSpan is a [trace.Span] that records the names of its events.
+type recordingSpan struct {
+	noop.Span
+	// events are the names of the added events.
+	events []string
+}
+
+// AddEvent records the name of the event.
+func (s *recordingSpan) AddEvent(name string, _ ...trace.EventOption) {
+	s.events = append(s.events, name)
+}
@@ -0,0 +1,323 @@
+package executors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Policy is a declarative configuration of the policies applied to an effector.
+// It can be loaded with the config module and builds a correctly ordered chain of policies with [Policy.Apply].
+//
+// The policies are applied from the outside in as follows:
+//
+//	Timeout → Retry → RateLimit → Bulkhead → CircuitBreaker → AttemptTimeout → Protection → effector
+//
+// So the Timeout limits the call including all retries, while the AttemptTimeout limits every single attempt.
+// The rate limit and bulkhead apply to every attempt and the circuit breaker records every attempt they let through,
+// so attempts rejected by the rate limit or the bulkhead don't count as failures of the circuit breaker.
+//
+// Example:
+//
+//	timeout: 10s
+//	attemptTimeout: 2s
+//	retry:
+//	  maxRetries: 3
+//	  backoff: full-jitter
+//	  initialDelay: 100ms
+//	  maxDelay: 1s
+//	circuitBreaker:
+//	  maxFailures: 5
+//	  resetTimeout: 30s
+type Policy struct {
+	// Timeout is the timeout of the whole call including all retries. Zero disables the timeout.
+	Timeout time.Duration `yaml:"timeout" mapstructure:"timeout"`
+	// AttemptTimeout is the timeout of every single attempt. Zero disables the timeout.
+	AttemptTimeout time.Duration `yaml:"attemptTimeout" mapstructure:"attemptTimeout"`
+	// Retry configures the retries. Nil disables retries.
+	Retry *RetryPolicy `yaml:"retry" mapstructure:"retry"`
+	// CircuitBreaker configures the circuit breaker. Nil disables the circuit breaker.
+	CircuitBreaker *CircuitBreakerPolicy `yaml:"circuitBreaker" mapstructure:"circuitBreaker"`
+	// RateLimit configures the rate limit. Nil disables the rate limit.
+	RateLimit *RateLimitPolicy `yaml:"rateLimit" mapstructure:"rateLimit"`
+	// Bulkhead configures the bulkhead. Nil disables the bulkhead.
+	Bulkhead *BulkheadPolicy `yaml:"bulkhead" mapstructure:"bulkhead"`
+	// Protection indicates if panics should be recovered and returned as errors.
+	Protection bool `yaml:"protection" mapstructure:"protection"`
+}
+
+// RetryPolicy is the declarative configuration of a [Retrier].
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of attempts.
+	MaxRetries int `yaml:"maxRetries" mapstructure:"maxRetries"`
+	// Backoff is the backoff strategy. Defaults to [BackoffExponential].
+	Backoff BackoffStrategy `yaml:"backoff" mapstructure:"backoff"`
+	// InitialDelay is the base delay of the backoff strategy. Defaults to one second.
+	InitialDelay time.Duration `yaml:"initialDelay" mapstructure:"initialDelay"`
+	// MaxDelay caps the delay of the backoff strategy. Zero disables the cap.
+	MaxDelay time.Duration `yaml:"maxDelay" mapstructure:"maxDelay"`
+	// MaxElapsedTime is the maximum amount of time spent on all attempts. Zero disables the limit.
+	MaxElapsedTime time.Duration `yaml:"maxElapsedTime" mapstructure:"maxElapsedTime"`
+}
+
+// BackoffStrategy is the name of a backoff strategy of a [RetryPolicy].
+type BackoffStrategy string
+
+const (
+	// BackoffConstant is the strategy of [ConstantBackoff].
+	BackoffConstant BackoffStrategy = "constant"
+	// BackoffLinear is the strategy of [LinearBackoff] with the initial delay as step.
+	BackoffLinear BackoffStrategy = "linear"
+	// BackoffExponential is the strategy of [ExponentialBackoff].
+	BackoffExponential BackoffStrategy = "exponential"
+	// BackoffFullJitter is the strategy of [FullJitterBackoff].
+	BackoffFullJitter BackoffStrategy = "full-jitter"
+	// BackoffEqualJitter is the strategy of [EqualJitterBackoff].
+	BackoffEqualJitter BackoffStrategy = "equal-jitter"
+	// BackoffDecorrelatedJitter is the strategy of [DecorrelatedJitterBackoff].
+	BackoffDecorrelatedJitter BackoffStrategy = "decorrelated-jitter"
+)
+
+// CircuitBreakerPolicy is the declarative configuration of a [Breaker].
+// If a window is configured, the circuit opens on the failure or slow call ratio of the window,
+// otherwise it opens after MaxFailures consecutive failures.
+type CircuitBreakerPolicy struct {
+	// MaxFailures is the number of consecutive failures after which the circuit opens.
+	MaxFailures int `yaml:"maxFailures" mapstructure:"maxFailures"`
+	// ResetTimeout is the amount of time the circuit stays open before it transitions to half-open.
+	ResetTimeout time.Duration `yaml:"resetTimeout" mapstructure:"resetTimeout"`
+	// HalfOpenMaxCalls is the maximum number of trial calls while the circuit is half-open.
+	HalfOpenMaxCalls int `yaml:"halfOpenMaxCalls" mapstructure:"halfOpenMaxCalls"`
+	// SuccessThreshold is the number of successful trial calls required to close the circuit again.
+	SuccessThreshold int `yaml:"successThreshold" mapstructure:"successThreshold"`
+	// WindowSize is the number of calls of a [CountWindow].
+	WindowSize int `yaml:"windowSize" mapstructure:"windowSize"`
+	// WindowDuration is the duration of a [TimeWindow].
+	WindowDuration time.Duration `yaml:"windowDuration" mapstructure:"windowDuration"`
+	// MinCalls is the minimum number of calls in the window before the circuit may open.
+	MinCalls int `yaml:"minCalls" mapstructure:"minCalls"`
+	// FailureRatio is the ratio of failed calls in the window at which the circuit opens.
+	FailureRatio float64 `yaml:"failureRatio" mapstructure:"failureRatio"`
+	// SlowCallRatio is the ratio of slow calls in the window at which the circuit opens.
+	SlowCallRatio float64 `yaml:"slowCallRatio" mapstructure:"slowCallRatio"`
+	// SlowCallDuration is the duration above which a call is considered slow.
+	SlowCallDuration time.Duration `yaml:"slowCallDuration" mapstructure:"slowCallDuration"`
+}
+
+// RateLimitPolicy is the declarative configuration of a [TokenBucket].
+type RateLimitPolicy struct {
+	// Rate is the number of calls allowed per second.
+	Rate float64 `yaml:"rate" mapstructure:"rate"`
+	// Burst is the maximum number of calls allowed at once. Defaults to 1.
+	Burst int `yaml:"burst" mapstructure:"burst"`
+}
+
+// BulkheadPolicy is the declarative configuration of a [Bulkhead].
+type BulkheadPolicy struct {
+	// MaxConcurrent is the maximum number of concurrent calls.
+	MaxConcurrent int `yaml:"maxConcurrent" mapstructure:"maxConcurrent"`
+	// MaxQueue is the maximum number of calls waiting for a free slot.
+	MaxQueue int `yaml:"maxQueue" mapstructure:"maxQueue"`
+}
+
+// IsEmpty returns true if no policy is configured.
+func (p Policy) IsEmpty() bool {
+	return reflect.DeepEqual(p, Policy{})
+}
+
+// Validate validates the policy and rejects contradictory settings.
+func (p Policy) Validate() error {
+	var err error
+	if p.Timeout < 0 {
+		err = errors.Join(err, errors.New("timeout must not be negative"))
+	}
+	if p.AttemptTimeout < 0 {
+		err = errors.Join(err, errors.New("attemptTimeout must not be negative"))
+	}
+	if p.Timeout > 0 && p.AttemptTimeout >= p.Timeout {
+		err = errors.Join(err, fmt.Errorf("attemptTimeout %v must be shorter than timeout %v", p.AttemptTimeout, p.Timeout))
+	}
+
+	if p.Retry != nil {
+		err = errors.Join(err, p.Retry.validate(p.Timeout))
+	}
+	if p.CircuitBreaker != nil {
+		err = errors.Join(err, p.CircuitBreaker.validate(p.AttemptTimeout))
+	}
+	if p.RateLimit != nil {
+		if p.RateLimit.Rate <= 0 {
+			err = errors.Join(err, errors.New("rateLimit.rate must be greater than 0"))
+		}
+		if p.RateLimit.Burst < 0 {
+			err = errors.Join(err, errors.New("rateLimit.burst must not be negative"))
+		}
+	}
+	if p.Bulkhead != nil {
+		if p.Bulkhead.MaxConcurrent < 1 {
+			err = errors.Join(err, errors.New("bulkhead.maxConcurrent must be at least 1"))
+		}
+		if p.Bulkhead.MaxQueue < 0 {
+			err = errors.Join(err, errors.New("bulkhead.maxQueue must not be negative"))
+		}
+	}
+	return err
+}
+
+// validate validates the retry policy against the overall timeout.
+func (r *RetryPolicy) validate(timeout time.Duration) error {
+	var err error
+	if r.MaxRetries < 1 {
+		err = errors.Join(err, errors.New("retry.maxRetries must be at least 1"))
+	}
+	if r.InitialDelay < 0 || r.MaxDelay < 0 || r.MaxElapsedTime < 0 {
+		err = errors.Join(err, errors.New("retry delays must not be negative"))
+	}
+	if r.MaxDelay > 0 && r.MaxDelay < r.InitialDelay {
+		err = errors.Join(err, fmt.Errorf("retry.maxDelay %v must not be shorter than retry.initialDelay %v", r.MaxDelay, r.InitialDelay))
+	}
+	if _, ok := backoffs[r.Backoff]; !ok {
+		err = errors.Join(err, fmt.Errorf("unsupported retry.backoff %q", r.Backoff))
+	}
+	if timeout > 0 && r.MaxRetries > 1 && r.initialDelay() >= timeout {
+		err = errors.Join(err, fmt.Errorf("retry.initialDelay %v leaves no time for a retry within timeout %v", r.initialDelay(), timeout))
+	}
+	if timeout > 0 && r.MaxElapsedTime > timeout {
+		err = errors.Join(err, fmt.Errorf("retry.maxElapsedTime %v exceeds timeout %v", r.MaxElapsedTime, timeout))
+	}
+	return err
+}
+
+// initialDelay returns the initial delay or its default.
+func (r *RetryPolicy) initialDelay() time.Duration {
+	if r.InitialDelay == 0 {
+		return time.Second
+	}
+	return r.InitialDelay
+}
+
+// backoffs are the constructors of all supported backoff strategies.
+var backoffs = map[BackoffStrategy]func(initial, maxDelay time.Duration) Backoff{
+	"":                 ExponentialBackoff,
+	BackoffConstant:    func(initial, _ time.Duration) Backoff { return ConstantBackoff(initial) },
+	BackoffLinear:      func(initial, maxDelay time.Duration) Backoff { return LinearBackoff(initial, initial, maxDelay) },
+	BackoffExponential: ExponentialBackoff,
+	BackoffFullJitter:  func(initial, maxDelay time.Duration) Backoff { return FullJitterBackoff(initial, maxDelay, nil) },
+	BackoffEqualJitter: func(initial, maxDelay time.Duration) Backoff { return EqualJitterBackoff(initial, maxDelay, nil) },
+	BackoffDecorrelatedJitter: func(initial, maxDelay time.Duration) Backoff {
+		return DecorrelatedJitterBackoff(initial, maxDelay, nil)
+	},
+}
+
+// retrier builds the [Retrier] of the retry policy.
+func (r *RetryPolicy) retrier(o options) *Retrier {
+	return &Retrier{
+		MaxRetries:     r.MaxRetries,
+		Backoff:        backoffs[r.Backoff](r.initialDelay(), r.MaxDelay),
+		MaxElapsedTime: r.MaxElapsedTime,
+		Observer:       o.observer,
+		Clock:          o.clock,
+	}
+}
+
+// validate validates the circuit breaker policy against the attempt timeout.
+func (c *CircuitBreakerPolicy) validate(attemptTimeout time.Duration) error {
+	var err error
+	if c.ResetTimeout <= 0 {
+		err = errors.Join(err, errors.New("circuitBreaker.resetTimeout must be greater than 0"))
+	}
+	if c.HalfOpenMaxCalls > 0 && c.SuccessThreshold > c.HalfOpenMaxCalls {
+		err = errors.Join(err, fmt.Errorf("circuitBreaker.successThreshold %d exceeds circuitBreaker.halfOpenMaxCalls %d", c.SuccessThreshold, c.HalfOpenMaxCalls))
+	}
+	if c.WindowSize > 0 && c.WindowDuration > 0 {
+		err = errors.Join(err, errors.New("circuitBreaker.windowSize and circuitBreaker.windowDuration are mutually exclusive"))
+	}
+	if c.FailureRatio < 0 || c.FailureRatio > 1 || c.SlowCallRatio < 0 || c.SlowCallRatio > 1 {
+		err = errors.Join(err, errors.New("circuitBreaker ratios must be between 0 and 1"))
+	}
+
+	windowed := c.WindowSize > 0 || c.WindowDuration > 0
+	switch {
+	case windowed && c.MaxFailures > 0:
+		err = errors.Join(err, errors.New("circuitBreaker.maxFailures can't be combined with a window"))
+	case windowed && c.FailureRatio == 0 && c.SlowCallRatio == 0:
+		err = errors.Join(err, errors.New("circuitBreaker window requires a failureRatio or slowCallRatio"))
+	case !windowed && (c.FailureRatio > 0 || c.SlowCallRatio > 0):
+		err = errors.Join(err, errors.New("circuitBreaker ratios require a windowSize or windowDuration"))
+	}
+
+	if c.SlowCallRatio > 0 && c.SlowCallDuration <= 0 {
+		err = errors.Join(err, errors.New("circuitBreaker.slowCallRatio requires a slowCallDuration"))
+	}
+	if attemptTimeout > 0 && c.SlowCallDuration >= attemptTimeout {
+		err = errors.Join(err, fmt.Errorf("circuitBreaker.slowCallDuration %v is never reached within attemptTimeout %v", c.SlowCallDuration, attemptTimeout))
+	}
+	return err
+}
+
+// breaker builds the [Breaker] of the circuit breaker policy.
+func (c *CircuitBreakerPolicy) breaker(o options) *Breaker {
+	opts := BreakerOptions{
+		MaxFailures:      c.MaxFailures,
+		ResetTimeout:     c.ResetTimeout,
+		HalfOpenMaxCalls: c.HalfOpenMaxCalls,
+		SuccessThreshold: c.SuccessThreshold,
+		Observer:         o.observer,
+		Clock:            o.clock,
+	}
+
+	window := WindowOptions{
+		MinCalls:         c.MinCalls,
+		FailureRatio:     c.FailureRatio,
+		SlowCallRatio:    c.SlowCallRatio,
+		SlowCallDuration: c.SlowCallDuration,
+	}
+	switch {
+	case c.WindowSize > 0:
+		opts.TripPolicy = CountWindow(c.WindowSize, window)
+	case c.WindowDuration > 0:
+		opts.TripPolicy = TimeWindow(c.WindowDuration, window)
+	}
+	return NewBreaker(opts)
+}
+
+// Apply returns an effector that runs the effector with all configured policies in their canonical order.
+// Stateful policies like the circuit breaker are created once per call of Apply and shared by all calls of the returned effector.
+// The options, e.g. [WithObserver], are passed on to all policies.
+// If the policy is invalid, the returned effector always returns the validation error.
+func (p Policy) Apply(effector Effector, opts ...Option) Effector {
+	if effector == nil {
+		return noopEffector
+	}
+	if err := p.Validate(); err != nil {
+		return func(_ context.Context) error {
+			return fmt.Errorf("invalid policy: %w", err)
+		}
+	}
+
+	o := newOptions(opts)
+	// The policies are applied from the inside out.
+	if p.Protection {
+		effector = Protector(effector, opts...)
+	}
+	if p.AttemptTimeout > 0 {
+		effector = Timeouter(p.AttemptTimeout, effector, opts...)
+	}
+	if p.CircuitBreaker != nil {
+		effector = p.CircuitBreaker.breaker(o).Wrap(effector)
+	}
+	if p.Bulkhead != nil {
+		effector = Bulkhead(p.Bulkhead.MaxConcurrent, p.Bulkhead.MaxQueue, effector, opts...)
+	}
+	if p.RateLimit != nil {
+		effector = Limited(NewTokenBucket(RateLimit(p.RateLimit.Rate), p.RateLimit.Burst, opts...), effector, opts...)
+	}
+	if p.Retry != nil {
+		effector = p.Retry.retrier(o).Retry(effector)
+	}
+	if p.Timeout > 0 {
+		effector = Timeouter(p.Timeout, effector, opts...)
+	}
+	return effector
+}
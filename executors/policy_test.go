@@ -0,0 +1,315 @@
+package executors
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  Policy
+		wantErr bool
+	}{
+		{
+			name: "empty policy",
+		},
+		{
+			name: "valid policy",
+			policy: Policy{
+				Timeout:        10 * time.Second,
+				AttemptTimeout: 2 * time.Second,
+				Retry:          &RetryPolicy{MaxRetries: 3, Backoff: BackoffFullJitter, InitialDelay: 100 * time.Millisecond, MaxDelay: time.Second},
+				CircuitBreaker: &CircuitBreakerPolicy{ResetTimeout: 30 * time.Second, WindowSize: 10, FailureRatio: 0.5, SlowCallRatio: 0.5, SlowCallDuration: time.Second},
+				RateLimit:      &RateLimitPolicy{Rate: 10},
+				Bulkhead:       &BulkheadPolicy{MaxConcurrent: 5, MaxQueue: 10},
+			},
+		},
+		{
+			name:    "negative timeout",
+			policy:  Policy{Timeout: -time.Second},
+			wantErr: true,
+		},
+		{
+			name:    "attempt timeout exceeds timeout",
+			policy:  Policy{Timeout: time.Second, AttemptTimeout: 2 * time.Second},
+			wantErr: true,
+		},
+		{
+			name:    "no retries",
+			policy:  Policy{Retry: &RetryPolicy{}},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported backoff",
+			policy:  Policy{Retry: &RetryPolicy{MaxRetries: 3, Backoff: "fibonacci"}},
+			wantErr: true,
+		},
+		{
+			name:    "max delay shorter than initial delay",
+			policy:  Policy{Retry: &RetryPolicy{MaxRetries: 3, InitialDelay: time.Second, MaxDelay: time.Millisecond}},
+			wantErr: true,
+		},
+		{
+			name:    "no time for retries",
+			policy:  Policy{Timeout: time.Second, Retry: &RetryPolicy{MaxRetries: 3, InitialDelay: 2 * time.Second}},
+			wantErr: true,
+		},
+		{
+			name:    "max elapsed time exceeds timeout",
+			policy:  Policy{Timeout: time.Second, Retry: &RetryPolicy{MaxRetries: 3, InitialDelay: time.Millisecond, MaxElapsedTime: time.Minute}},
+			wantErr: true,
+		},
+		{
+			name:    "circuit breaker without reset timeout",
+			policy:  Policy{CircuitBreaker: &CircuitBreakerPolicy{MaxFailures: 3}},
+			wantErr: true,
+		},
+		{
+			name:    "count and time window",
+			policy:  Policy{CircuitBreaker: &CircuitBreakerPolicy{ResetTimeout: time.Second, WindowSize: 10, WindowDuration: time.Minute, FailureRatio: 0.5}},
+			wantErr: true,
+		},
+		{
+			name:    "max failures with window",
+			policy:  Policy{CircuitBreaker: &CircuitBreakerPolicy{ResetTimeout: time.Second, MaxFailures: 3, WindowSize: 10, FailureRatio: 0.5}},
+			wantErr: true,
+		},
+		{
+			name:    "ratio without window",
+			policy:  Policy{CircuitBreaker: &CircuitBreakerPolicy{ResetTimeout: time.Second, FailureRatio: 0.5}},
+			wantErr: true,
+		},
+		{
+			name:    "success threshold exceeds half-open calls",
+			policy:  Policy{CircuitBreaker: &CircuitBreakerPolicy{ResetTimeout: time.Second, HalfOpenMaxCalls: 1, SuccessThreshold: 2}},
+			wantErr: true,
+		},
+		{
+			name:    "slow call duration exceeds attempt timeout",
+			policy:  Policy{AttemptTimeout: time.Second, CircuitBreaker: &CircuitBreakerPolicy{ResetTimeout: time.Second, WindowSize: 10, SlowCallRatio: 0.5, SlowCallDuration: 2 * time.Second}},
+			wantErr: true,
+		},
+		{
+			name:    "zero rate",
+			policy:  Policy{RateLimit: &RateLimitPolicy{}},
+			wantErr: true,
+		},
+		{
+			name:    "empty bulkhead",
+			policy:  Policy{Bulkhead: &BulkheadPolicy{}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policy.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Policy.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPolicy_IsEmpty(t *testing.T) {
+	if !(Policy{}).IsEmpty() {
+		t.Error("Policy.IsEmpty() = false, want true")
+	}
+	if (Policy{Timeout: time.Second}).IsEmpty() {
+		t.Error("Policy.IsEmpty() = true, want false")
+	}
+}
+
+func TestPolicy_Apply(t *testing.T) {
+	errTest := errors.New("test error")
+	tests := []struct {
+		name      string
+		policy    Policy
+		effector  Effector
+		wantCalls int
+		wantErr   bool
+		errIs     error
+	}{
+		{
+			name:      "empty policy",
+			effector:  func(context.Context) error { return nil },
+			wantCalls: 1,
+		},
+		{
+			name:      "retries until success",
+			policy:    Policy{Retry: &RetryPolicy{MaxRetries: 3, Backoff: BackoffConstant, InitialDelay: time.Millisecond}},
+			effector:  failTimes(2, errTest),
+			wantCalls: 3,
+		},
+		{
+			name: "breaker stops retries",
+			policy: Policy{
+				Retry:          &RetryPolicy{MaxRetries: 5, Backoff: BackoffConstant, InitialDelay: time.Millisecond},
+				CircuitBreaker: &CircuitBreakerPolicy{MaxFailures: 2, ResetTimeout: time.Minute},
+			},
+			effector:  func(context.Context) error { return errTest },
+			wantCalls: 2,
+			wantErr:   true,
+			errIs:     ErrCircuitOpen{},
+		},
+		{
+			name: "attempt timeout is retried",
+			policy: Policy{
+				Timeout:        time.Second,
+				AttemptTimeout: 10 * time.Millisecond,
+				Retry:          &RetryPolicy{MaxRetries: 3, Backoff: BackoffConstant, InitialDelay: time.Millisecond},
+			},
+			effector:  blockTimes(1),
+			wantCalls: 2,
+		},
+		{
+			name:      "panic is recovered",
+			policy:    Policy{Protection: true},
+			effector:  func(context.Context) error { panic("test") },
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:     "invalid policy",
+			policy:   Policy{Bulkhead: &BulkheadPolicy{}},
+			effector: func(context.Context) error { return nil },
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			effector := tt.policy.Apply(func(ctx context.Context) error {
+				calls++
+				return tt.effector(ctx)
+			})
+
+			err := effector.Do(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Policy.Apply() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.errIs != nil && !errors.Is(err, tt.errIs) {
+				t.Errorf("Policy.Apply() error = %v, want %v", err, tt.errIs)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("Policy.Apply() calls = %d, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestPolicy_Apply_Rejections(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy Policy
+		// reject returns the error of a call rejected by the policy.
+		// The calls of the effector signal started and block until release is closed.
+		reject func(effector Effector, started, release chan struct{}) error
+	}{
+		{
+			name: "bulkhead full",
+			policy: Policy{
+				Bulkhead:       &BulkheadPolicy{MaxConcurrent: 1},
+				CircuitBreaker: &CircuitBreakerPolicy{MaxFailures: 1, ResetTimeout: time.Minute},
+			},
+			reject: func(effector Effector, started, release chan struct{}) error {
+				done := make(chan struct{})
+				go func() {
+					defer close(done)
+					_ = effector.Do(context.Background())
+				}()
+				<-started
+				err := effector.Do(context.Background())
+				close(release)
+				<-done
+				return err
+			},
+		},
+		{
+			name: "rate limit exceeded",
+			policy: Policy{
+				RateLimit:      &RateLimitPolicy{Rate: 20, Burst: 1},
+				CircuitBreaker: &CircuitBreakerPolicy{MaxFailures: 1, ResetTimeout: time.Minute},
+			},
+			reject: func(effector Effector, _, release chan struct{}) error {
+				close(release)
+				if err := effector.Do(context.Background()); err != nil {
+					return err
+				}
+				ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+				defer cancel()
+				return effector.Do(ctx)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			started, release := make(chan struct{}, 1), make(chan struct{})
+			effector := tt.policy.Apply(func(context.Context) error {
+				select {
+				case started <- struct{}{}:
+				default:
+				}
+				<-release
+				return nil
+			})
+
+			if err := tt.reject(effector, started, release); err == nil {
+				t.Fatal("Policy.Apply() error = nil, want rejection")
+			}
+			// The rejection must not have opened the circuit.
+			if err := effector.Do(context.Background()); err != nil {
+				t.Errorf("Policy.Apply() error = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_Retrier_Backoff(t *testing.T) {
+	for strategy := range backoffs {
+		t.Run(string(strategy), func(t *testing.T) {
+			r := &RetryPolicy{MaxRetries: 3, Backoff: strategy, InitialDelay: time.Millisecond, MaxDelay: time.Hour}
+			retrier := r.retrier(newOptions(nil))
+			if retrier.Backoff == nil {
+				t.Fatal("RetryPolicy.retrier() Backoff = nil")
+			}
+
+			// A call far into its retries must not influence the first delay of another call.
+			for i := range uint(20) {
+				retrier.Backoff(i)
+			}
+			if got := retrier.Backoff(0); got > 3*time.Millisecond {
+				t.Errorf("RetryPolicy.retrier() first delay = %v, want at most %v", got, 3*time.Millisecond)
+			}
+		})
+	}
+}
+
+// failTimes returns an effector that fails the given number of times before it succeeds.
+func failTimes(n int, err error) Effector {
+	calls := 0
+	return func(context.Context) error {
+		calls++
+		if calls <= n {
+			return err
+		}
+		return nil
+	}
+}
+
+// blockTimes returns an effector that blocks until its context is done the given number of times before it succeeds.
+func blockTimes(n int) Effector {
+	calls := 0
+	return func(ctx context.Context) error {
+		calls++
+		if calls <= n {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return nil
+	}
+}
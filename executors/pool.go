@@ -0,0 +1,180 @@
+package executors
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+)
+
+// ErrPoolClosed is the error returned when a task is submitted to a closed [Pool].
+type ErrPoolClosed struct{}
+
+// Error returns the error message.
+func (e ErrPoolClosed) Error() string {
+	return "pool closed"
+}
+
+// PoolOptions configures a [Pool].
+type PoolOptions struct {
+	// Workers is the number of tasks that run concurrently. Defaults to [runtime.GOMAXPROCS].
+	Workers int
+	// QueueSize is the number of submitted tasks that are buffered until a worker is free.
+	// Once the queue is full, [Pool.Submit] blocks, which applies backpressure to the producer.
+	QueueSize int
+	// FailFast cancels the context of the pool on the first error, so that running tasks are
+	// canceled, queued tasks are skipped and further submissions are rejected.
+	// [Pool.Wait] then only returns the first error, otherwise it returns all errors as wrapped [errors.Join] error.
+	FailFast bool
+}
+
+// Pool is a bounded pool of workers that run submitted effectors.
+// Each task is protected with a [Protector], so a panicking task doesn't crash the process.
+//
+// Safe to use concurrently.
+//
+// Example:
+//
+//	pool := executors.NewPool(ctx, executors.PoolOptions{Workers: 8, QueueSize: 64})
+//	for _, item := range items {
+//		if err := pool.Submit(ctx, process(item)); err != nil {
+//			break
+//		}
+//	}
+//	err := pool.Wait()
+type Pool struct {
+	// ctx is the context passed to all tasks.
+	ctx context.Context
+	// cancel cancels the context of the pool.
+	cancel context.CancelFunc
+	// failFast is true if the pool stops on the first error.
+	failFast bool
+	// tasks is the queue of submitted tasks.
+	tasks chan Effector
+	// workers tracks the running workers.
+	workers sync.WaitGroup
+
+	// mu protects closed and guards sending on tasks.
+	mu sync.RWMutex
+	// closed is true once the pool doesn't accept further tasks.
+	closed bool
+
+	// errMu protects errs.
+	errMu sync.Mutex
+	// errs are the errors returned by the tasks.
+	errs []error
+}
+
+// NewPool creates a new [Pool] and starts its workers.
+// The given context is passed to all tasks. Call [Pool.Wait] to release the workers.
+func NewPool(ctx context.Context, opts PoolOptions) *Pool {
+	if opts.Workers < 1 {
+		opts.Workers = runtime.GOMAXPROCS(0)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+
+	p := &Pool{
+		ctx:      ctx,
+		cancel:   cancel,
+		failFast: opts.FailFast,
+		tasks:    make(chan Effector, max(opts.QueueSize, 0)),
+	}
+	for range opts.Workers {
+		p.workers.Go(p.work)
+	}
+	return p
+}
+
+// Submit queues the effector to be run by a worker.
+// It blocks while the queue is full until a worker is free or the given context is done.
+// Returns [ErrPoolClosed] if the pool has been closed, or the error that stopped the pool if it fails fast.
+// Once the pool is stopped, no further task is queued.
+func (p *Pool) Submit(ctx context.Context, effector Effector) error {
+	if effector == nil {
+		return nil
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return ErrPoolClosed{}
+	}
+	// The select below picks randomly if the queue has room and the pool is already stopped.
+	if p.ctx.Err() != nil {
+		return p.stopped()
+	}
+
+	select {
+	case p.tasks <- effector:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-p.ctx.Done():
+		return p.stopped()
+	}
+}
+
+// stopped returns the recorded errors of the stopped pool or the error of its context if there are none.
+func (p *Pool) stopped() error {
+	if err := p.err(); err != nil {
+		return err
+	}
+	return p.ctx.Err()
+}
+
+// Close stops accepting new tasks. Already submitted tasks are still run.
+// It is safe to call Close multiple times.
+func (p *Pool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.closed {
+		p.closed = true
+		close(p.tasks)
+	}
+}
+
+// Wait closes the pool, waits for all submitted tasks to finish and returns their errors.
+func (p *Pool) Wait() error {
+	p.Close()
+	p.workers.Wait()
+	defer p.cancel()
+	return p.err()
+}
+
+// work runs queued tasks until the queue is closed.
+// Once the context of the pool is done, queued tasks are skipped. Without fail fast, the error of the context is
+// recorded for every skipped task.
+func (p *Pool) work() {
+	for task := range p.tasks {
+		if err := p.ctx.Err(); err != nil {
+			if !p.failFast {
+				p.record(err)
+			}
+			continue
+		}
+		if err := Protector(task)(p.ctx); err != nil {
+			p.record(err)
+		}
+	}
+}
+
+// record stores the error of a task and cancels the pool if it fails fast.
+func (p *Pool) record(err error) {
+	p.errMu.Lock()
+	defer p.errMu.Unlock()
+	if p.failFast {
+		if len(p.errs) == 0 {
+			p.errs = append(p.errs, err)
+			p.cancel()
+		}
+		return
+	}
+	p.errs = append(p.errs, err)
+}
+
+// err returns the recorded errors.
+func (p *Pool) err() error {
+	p.errMu.Lock()
+	defer p.errMu.Unlock()
+	return errors.Join(p.errs...)
+}
@@ -0,0 +1,221 @@
+package executors
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPool(t *testing.T) {
+	errFailed := errors.New("failed")
+	tests := []struct {
+		name      string
+		opts      PoolOptions
+		tasks     int
+		task      func(i int) Effector
+		wantErrs  int
+		wantCalls int32
+	}{
+		{
+			name:      "all tasks succeed",
+			opts:      PoolOptions{Workers: 4, QueueSize: 2},
+			tasks:     50,
+			task:      func(i int) Effector { return noopEffector },
+			wantCalls: 50,
+		},
+		{
+			name:  "collects all errors",
+			opts:  PoolOptions{Workers: 2},
+			tasks: 10,
+			task: func(i int) Effector {
+				return func(ctx context.Context) error {
+					if i%2 == 0 {
+						return errFailed
+					}
+					return nil
+				}
+			},
+			wantErrs:  5,
+			wantCalls: 10,
+		},
+		{
+			name:  "recovers panics",
+			opts:  PoolOptions{Workers: 2},
+			tasks: 3,
+			task: func(i int) Effector {
+				return func(ctx context.Context) error { panic("boom") }
+			},
+			wantErrs:  3,
+			wantCalls: 3,
+		},
+		{
+			name:  "default workers",
+			tasks: 5,
+			task:  func(i int) Effector { return noopEffector },
+			// Every task is run.
+			wantCalls: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := NewPool(context.Background(), tt.opts)
+			var calls atomic.Int32
+			for i := range tt.tasks {
+				task := tt.task(i)
+				err := pool.Submit(context.Background(), func(ctx context.Context) error {
+					calls.Add(1)
+					return task(ctx)
+				})
+				if err != nil {
+					t.Fatalf("Pool.Submit() error = %v", err)
+				}
+			}
+
+			err := pool.Wait()
+			gotErrs := 0
+			if err != nil {
+				gotErrs = len(err.(interface{ Unwrap() []error }).Unwrap())
+			}
+			if gotErrs != tt.wantErrs {
+				t.Errorf("Pool.Wait() errors = %d, want %d", gotErrs, tt.wantErrs)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("Pool ran %d tasks, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestPool_Workers(t *testing.T) {
+	pool := NewPool(context.Background(), PoolOptions{Workers: 3})
+	var running, peak atomic.Int32
+	for range 30 {
+		_ = pool.Submit(context.Background(), func(ctx context.Context) error {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			return nil
+		})
+	}
+	if err := pool.Wait(); err != nil {
+		t.Fatalf("Pool.Wait() error = %v", err)
+	}
+	if got := peak.Load(); got > 3 {
+		t.Errorf("Pool ran %d tasks concurrently, want at most 3", got)
+	}
+}
+
+func TestPool_FailFast(t *testing.T) {
+	errFailed := errors.New("failed")
+	pool := NewPool(context.Background(), PoolOptions{Workers: 1, QueueSize: 10, FailFast: true})
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	_ = pool.Submit(context.Background(), func(ctx context.Context) error {
+		calls.Add(1)
+		<-release
+		return errFailed
+	})
+	for range 5 {
+		_ = pool.Submit(context.Background(), func(ctx context.Context) error {
+			calls.Add(1)
+			return nil
+		})
+	}
+	close(release)
+
+	if err := pool.Wait(); !errors.Is(err, errFailed) {
+		t.Fatalf("Pool.Wait() error = %v, want %v", err, errFailed)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("Pool ran %d tasks, want 1", got)
+	}
+}
+
+func TestPool_FailFast_Submit(t *testing.T) {
+	errFailed := errors.New("failed")
+	pool := NewPool(context.Background(), PoolOptions{Workers: 1, QueueSize: 100, FailFast: true})
+	_ = pool.Submit(context.Background(), func(ctx context.Context) error { return errFailed })
+	<-pool.ctx.Done()
+
+	// The queue has room, but a stopped pool must reject every submission.
+	var calls atomic.Int32
+	for range 100 {
+		err := pool.Submit(context.Background(), func(ctx context.Context) error {
+			calls.Add(1)
+			return nil
+		})
+		if !errors.Is(err, errFailed) {
+			t.Fatalf("Pool.Submit() error = %v, want %v", err, errFailed)
+		}
+	}
+
+	if err := pool.Wait(); !errors.Is(err, errFailed) {
+		t.Fatalf("Pool.Wait() error = %v, want %v", err, errFailed)
+	}
+	if got := calls.Load(); got != 0 {
+		t.Errorf("Pool ran %d tasks after it stopped, want 0", got)
+	}
+}
+
+func TestPool_Canceled_SkipsQueued(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	pool := NewPool(ctx, PoolOptions{Workers: 1, QueueSize: 10})
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	_ = pool.Submit(context.Background(), func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+	for range 5 {
+		_ = pool.Submit(context.Background(), func(ctx context.Context) error {
+			calls.Add(1)
+			return nil
+		})
+	}
+	cancel()
+	close(release)
+
+	if err := pool.Wait(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Pool.Wait() error = %v, want %v", err, context.Canceled)
+	}
+	if got := calls.Load(); got != 0 {
+		t.Errorf("Pool ran %d queued tasks after its context was canceled, want 0", got)
+	}
+}
+
+func TestPool_Submit_Backpressure(t *testing.T) {
+	pool := NewPool(context.Background(), PoolOptions{Workers: 1})
+	release := make(chan struct{})
+	_ = pool.Submit(context.Background(), func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := pool.Submit(ctx, noopEffector); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Pool.Submit() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	close(release)
+	if err := pool.Wait(); err != nil {
+		t.Fatalf("Pool.Wait() error = %v", err)
+	}
+	if err := pool.Submit(context.Background(), noopEffector); !errors.Is(err, ErrPoolClosed{}) {
+		t.Errorf("Pool.Submit() after Wait() error = %v, want %v", err, ErrPoolClosed{})
+	}
+	if err := pool.Submit(context.Background(), nil); err != nil {
+		t.Errorf("Pool.Submit() with nil effector error = %v, want nil", err)
+	}
+}
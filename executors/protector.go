@@ -1,25 +1,69 @@
 package executors
 
 import (
+	"bytes"
 	"context"
-	"errors"
 	"fmt"
+	"runtime/debug"
+	"strconv"
 )
 
-// Protector returns an effector that recovers from panics and returns them as errors.
-func Protector(effector Effector) Effector {
+// PanicError is the error returned when a panic is recovered.
+// If the recovered value is an error, it can be unwrapped.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+	// Stack is the stack trace of the panicking goroutine.
+	Stack []byte
+	// Goroutine is the ID of the panicking goroutine or 0 if it is unknown.
+	Goroutine uint64
+}
+
+// newPanicError creates a new [PanicError] with the recovered value and the stack trace.
+func newPanicError(value any, stack []byte) *PanicError {
+	return &PanicError{Value: value, Stack: stack, Goroutine: goroutineID(stack)}
+}
+
+// Error returns the error message.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the recovered value if it is an error.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// goroutineID parses the goroutine ID from the first line of a stack trace, e.g. "goroutine 7 [running]:".
+func goroutineID(stack []byte) uint64 {
+	line, _, _ := bytes.Cut(stack, []byte("\n"))
+	fields := bytes.Fields(line)
+	if len(fields) < 2 || !bytes.Equal(fields[0], []byte("goroutine")) {
+		return 0
+	}
+	id, err := strconv.ParseUint(string(fields[1]), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+// Protector returns an effector that recovers from panics and returns them as [*PanicError].
+func Protector(effector Effector, opts ...Option) Effector {
 	if effector == nil {
 		return noopEffector
 	}
+	o := newOptions(opts)
 
 	return func(ctx context.Context) (err error) {
 		defer func() {
 			if r := recover(); r != nil {
-				pErr, ok := r.(error)
-				if !ok {
-					pErr = fmt.Errorf("%v", r)
-				}
-				err = errors.Join(err, pErr)
+				stack := debug.Stack()
+				o.observer.OnPanic(ctx, r, stack)
+				err = newPanicError(r, stack)
 			}
 		}()
 
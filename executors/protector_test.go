@@ -43,3 +43,65 @@ func TestProtector(t *testing.T) {
 		})
 	}
 }
+
+func TestProtector_PanicError(t *testing.T) {
+	errTest := errors.New("test error")
+	tests := []struct {
+		name      string
+		value     any
+		wantValue any
+		wantIs    error
+	}{
+		{
+			name:      "string value",
+			value:     "test",
+			wantValue: "test",
+		},
+		{
+			name:      "error value",
+			value:     errTest,
+			wantValue: errTest,
+			wantIs:    errTest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Protector(func(context.Context) error { panic(tt.value) })(context.Background())
+
+			panicErr, ok := err.(*PanicError)
+			if !ok {
+				t.Fatalf("Protector() error = %v, want %T", err, panicErr)
+			}
+			if panicErr.Value != tt.wantValue {
+				t.Errorf("PanicError.Value = %v, want %v", panicErr.Value, tt.wantValue)
+			}
+			if len(panicErr.Stack) == 0 || panicErr.Goroutine == 0 {
+				t.Errorf("PanicError.Stack = %q, Goroutine = %d, want stack and goroutine", panicErr.Stack, panicErr.Goroutine)
+			}
+			if tt.wantIs != nil && !errors.Is(err, tt.wantIs) {
+				t.Errorf("Protector() error = %v, want to wrap %v", err, tt.wantIs)
+			}
+		})
+	}
+}
+
+func Test_goroutineID(t *testing.T) {
+	tests := []struct {
+		name  string
+		stack string
+		want  uint64
+	}{
+		{name: "stack trace", stack: "goroutine 42 [running]:\nmain.main()", want: 42},
+		{name: "empty stack", stack: "", want: 0},
+		{name: "invalid stack", stack: "goroutine x [running]:", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := goroutineID([]byte(tt.stack)); got != tt.want {
+				t.Errorf("goroutineID() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
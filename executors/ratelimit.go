@@ -26,6 +26,11 @@ func (e ErrWaitRateLimit) Error() string {
 	return fmt.Sprintf("wait rate limit: %v", e.Err)
 }
 
+// Unwrap returns the underlying error.
+func (e ErrWaitRateLimit) Unwrap() error {
+	return e.Err
+}
+
 // RateLimit is the rate limit for the rate limiter.
 // It is an alias for rate.Limit.
 //
@@ -33,7 +38,9 @@ func (e ErrWaitRateLimit) Error() string {
 type RateLimit = rate.Limit
 
 // RateLimiter runs the effector with the specified rate limit.
-func RateLimiter(r RateLimit, effector Effector) Effector {
+// Each call of RateLimiter creates its own [TokenBucket] with a burst of 1.
+// Use [Limited] to configure the burst or to share a limit between effectors.
+func RateLimiter(r RateLimit, effector Effector, opts ...Option) Effector {
 	if effector == nil {
 		return noopEffector
 	}
@@ -42,10 +49,23 @@ func RateLimiter(r RateLimit, effector Effector) Effector {
 			return ErrInvalidRateLimit{}
 		}
 	}
+	return Limited(NewTokenBucket(r, 1, opts...), effector, opts...)
+}
+
+// Limited runs the effector once the given [Limiter] permits it.
+func Limited(limiter Limiter, effector Effector, opts ...Option) Effector {
+	if effector == nil {
+		return noopEffector
+	}
+	if limiter == nil {
+		return func(_ context.Context) error {
+			return ErrInvalidRateLimit{}
+		}
+	}
+	o := newOptions(opts)
 
-	limiter := rate.NewLimiter(r, 1)
 	return func(ctx context.Context) error {
-		if err := limiter.Wait(ctx); err != nil {
+		if err := wait(ctx, limiter, o); err != nil {
 			if errors.Is(err, context.Canceled) {
 				return err
 			}
@@ -54,3 +74,33 @@ func RateLimiter(r RateLimit, effector Effector) Effector {
 		return effector(ctx)
 	}
 }
+
+// errWaitExceedsDeadline is the error returned when waiting for the rate limit would exceed the context deadline.
+var errWaitExceedsDeadline = errors.New("rate: Wait(n=1) would exceed context deadline")
+
+// wait blocks until the limiter permits a call or the context is done.
+// The observer of the options is notified if the call has to wait.
+func wait(ctx context.Context, limiter Limiter, o options) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	reservation, err := limiter.Reserve(ctx)
+	if err != nil {
+		return err
+	}
+	if reservation.Delay <= 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && deadline.Sub(o.clock.Now()) < reservation.Delay {
+		reservation.cancel()
+		return errWaitExceedsDeadline
+	}
+
+	o.observer.OnRateLimited(ctx, reservation.Delay)
+	if err := sleep(ctx, o.clock, reservation.Delay); err != nil {
+		reservation.cancel()
+		return err
+	}
+	return nil
+}
@@ -2,6 +2,8 @@ package executors
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"time"
 )
 
@@ -12,8 +14,47 @@ type Backoff func(retries uint) time.Duration
 type Retrier struct {
 	MaxRetries int
 	Backoff    Backoff
+	// RetryIf reports whether an error should be retried.
+	// If not set, [DefaultRetryIf] is used, which doesn't retry [Permanent] errors and [context.Canceled].
+	// Errors marked with [Permanent] are never retried, regardless of RetryIf.
+	RetryIf func(error) bool
+	// MaxElapsedTime is the maximum amount of time spent on all attempts and delays.
+	// No further retry is started if its delay would exceed the remaining time. A value of zero disables the limit.
+	MaxElapsedTime time.Duration
+	// Budget is the retry budget consumed by each retry. It can be shared between many retriers.
+	// A nil budget allows unlimited retries.
+	Budget *RetryBudget
+	// Observer is notified before every retry.
+	Observer Observer
+	// Clock measures the elapsed time and the delays. Defaults to [RealClock].
+	Clock Clock
 }
 
+// ErrRetriesExhausted is the error returned when a [Retrier] stops retrying
+// because its MaxElapsedTime or its [RetryBudget] has been used up.
+type ErrRetriesExhausted struct {
+	// Attempts is the number of attempts that have been made.
+	Attempts int
+	// Elapsed is the amount of time spent on all attempts and delays.
+	Elapsed time.Duration
+	// Err is the error returned by the last attempt.
+	Err error
+	// BudgetExhausted is true if retrying stopped because the retry budget has been used up.
+	BudgetExhausted bool
+}
+
+// Error returns the error message.
+func (e *ErrRetriesExhausted) Error() string {
+	reason := "max elapsed time exceeded"
+	if e.BudgetExhausted {
+		reason = "retry budget exhausted"
+	}
+	return fmt.Sprintf("%s after %d attempts in %v: %v", reason, e.Attempts, e.Elapsed, e.Err)
+}
+
+// Unwrap returns the error of the last attempt.
+func (e *ErrRetriesExhausted) Unwrap() error { return e.Err }
+
 // DefaultRetrier is the default retrier that retries 3 times with the default backoff.
 var DefaultRetrier = Retrier{
 	MaxRetries: 3,
@@ -22,6 +63,8 @@ var DefaultRetrier = Retrier{
 
 // Retry retries the effector a number of times with a delay between each retry.
 // If no backoff function is provided, the default backoff function is used.
+// Errors marked with [Permanent] and errors for which RetryIf returns false are returned immediately.
+// If MaxElapsedTime or the retry budget is used up, an [ErrRetriesExhausted] is returned.
 func (r *Retrier) Retry(effector Effector) Effector {
 	if effector == nil {
 		return noopEffector
@@ -30,23 +73,106 @@ func (r *Retrier) Retry(effector Effector) Effector {
 	if r.Backoff == nil {
 		r.Backoff = DefaultBackoff
 	}
+	retryIf := r.RetryIf
+	if retryIf == nil {
+		retryIf = DefaultRetryIf
+	}
+	observer := observerOrNoop(r.Observer)
+	clock := clockOrReal(r.Clock)
 
 	return func(ctx context.Context) (err error) {
+		start := clock.Now()
 		for i := 0; i < r.MaxRetries; i++ {
 			err = effector(ctx)
 			if err == nil {
+				r.Budget.deposit()
 				return nil
 			}
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			case <-time.After(r.Backoff(uint(i))):
+			if IsPermanent(err) || !retryIf(err) || i == r.MaxRetries-1 {
+				return err
+			}
+
+			delay := r.Backoff(uint(i))
+			if r.MaxElapsedTime > 0 && clock.Now().Sub(start)+delay > r.MaxElapsedTime {
+				return &ErrRetriesExhausted{Attempts: i + 1, Elapsed: clock.Now().Sub(start), Err: err}
+			}
+			if !r.Budget.withdraw() {
+				return &ErrRetriesExhausted{Attempts: i + 1, Elapsed: clock.Now().Sub(start), Err: err, BudgetExhausted: true}
+			}
+			observer.OnRetry(ctx, i+1, err, delay)
+
+			if err := sleep(ctx, clock, delay); err != nil {
+				return err
 			}
 		}
 		return err
 	}
 }
 
+// RetryBudget is a token bucket that limits retries to a ratio of successful calls.
+// Every successful call deposits a fraction of a token and every retry withdraws a whole token.
+// This prevents retries from multiplying the load on a dependency during an outage.
+//
+// Safe to use concurrently.
+type RetryBudget struct {
+	// ratio is the number of tokens deposited per successful call.
+	ratio float64
+	// capacity is the maximum number of tokens the budget holds.
+	capacity float64
+
+	// mu protects tokens.
+	mu sync.Mutex
+	// tokens is the number of tokens currently available.
+	tokens float64
+}
+
+// NewRetryBudget creates a new [RetryBudget] that allows ratio retries per successful call, e.g. 0.1 for 10%.
+// The budget starts full and holds at most maxRetries tokens, which allows bursts of retries after a quiet period.
+//
+// Example:
+//
+//	budget := executors.NewRetryBudget(0.1, 10)
+//	retrier := executors.Retrier{MaxRetries: 3, Budget: budget}
+//	a := taskA.WithRetry(retrier)
+//	b := taskB.WithRetry(retrier)
+func NewRetryBudget(ratio float64, maxRetries int) *RetryBudget {
+	capacity := float64(max(maxRetries, 0))
+	return &RetryBudget{ratio: max(ratio, 0), capacity: capacity, tokens: capacity}
+}
+
+// Available returns the number of retries currently available.
+func (b *RetryBudget) Available() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return int(b.tokens)
+}
+
+// deposit adds tokens for a successful call.
+// A nil budget is a no-op.
+func (b *RetryBudget) deposit() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = min(b.tokens+b.ratio, b.capacity)
+}
+
+// withdraw takes a token for a retry and reports whether it was available.
+// A nil budget always allows the retry.
+func (b *RetryBudget) withdraw() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
 // Retry retries the effector a number of times with a delay between each retry.
 // If the backoff function of the default retrier is nil, the default backoff function is used.
 // Safe to use concurrently.
@@ -67,7 +193,8 @@ func SetBackoff(backoff Backoff) {
 }
 
 // DefaultBackoff calculates the delay for the next retry.
-// The delay is calculated as 2^retries seconds.
+// The delay is calculated as 2^retries seconds and is not capped.
+// Use [ExponentialBackoff] or one of the jittered backoffs like [FullJitterBackoff] for a capped delay.
 func DefaultBackoff(retries uint) time.Duration {
 	return time.Duration(1<<retries) * time.Second
 }
@@ -184,3 +184,143 @@ func TestRetrier_DefaultBackoff(t *testing.T) {
 		})
 	}
 }
+
+func TestRetrier_Retry_RetryIf(t *testing.T) {
+	t.Parallel()
+
+	errInvalid := errors.New("invalid")
+	tests := []struct {
+		name      string
+		retryIf   func(error) bool
+		err       error
+		wantCalls int
+	}{
+		{
+			name:      "retries plain errors",
+			err:       errors.New("failed"),
+			wantCalls: 3,
+		},
+		{
+			name:      "permanent error is not retried",
+			err:       Permanent(errInvalid),
+			wantCalls: 1,
+		},
+		{
+			name:      "context canceled is not retried",
+			err:       context.Canceled,
+			wantCalls: 1,
+		},
+		{
+			name:      "custom classifier",
+			retryIf:   func(err error) bool { return !errors.Is(err, errInvalid) },
+			err:       errInvalid,
+			wantCalls: 1,
+		},
+		{
+			name:      "permanent error wins over custom classifier",
+			retryIf:   func(err error) bool { return true },
+			err:       Permanent(errInvalid),
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retrier := &Retrier{
+				MaxRetries: 3,
+				Backoff:    func(retries uint) time.Duration { return 0 },
+				RetryIf:    tt.retryIf,
+			}
+
+			calls := 0
+			err := retrier.Retry(func(ctx context.Context) error {
+				calls++
+				return tt.err
+			})(context.Background())
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Retrier.Retry() error = %v, want %v", err, tt.err)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("Retrier.Retry() calls = %d, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetrier_Retry_MaxElapsedTime(t *testing.T) {
+	t.Parallel()
+
+	errFailed := errors.New("failed")
+	retrier := &Retrier{
+		MaxRetries:     10,
+		Backoff:        func(retries uint) time.Duration { return 20 * time.Millisecond },
+		MaxElapsedTime: 50 * time.Millisecond,
+	}
+
+	calls := 0
+	err := retrier.Retry(func(ctx context.Context) error {
+		calls++
+		return errFailed
+	})(context.Background())
+
+	var exhausted *ErrRetriesExhausted
+	if !errors.As(err, &exhausted) {
+		t.Fatalf("Retrier.Retry() error = %v, want ErrRetriesExhausted", err)
+	}
+	if !errors.Is(err, errFailed) {
+		t.Errorf("Retrier.Retry() error = %v, want wrapped %v", err, errFailed)
+	}
+	if exhausted.Attempts != 3 || calls != 3 {
+		t.Errorf("Retrier.Retry() attempts = %d, calls = %d, want 3", exhausted.Attempts, calls)
+	}
+	if exhausted.BudgetExhausted {
+		t.Errorf("Retrier.Retry() BudgetExhausted = true, want false")
+	}
+}
+
+func TestRetrier_Retry_Budget(t *testing.T) {
+	t.Parallel()
+
+	budget := NewRetryBudget(0.5, 2)
+	retrier := Retrier{
+		MaxRetries: 3,
+		Backoff:    func(retries uint) time.Duration { return 0 },
+		Budget:     budget,
+	}
+	failing := Effector(func(ctx context.Context) error { return errors.New("failed") }).WithRetry(retrier)
+	succeeding := Effector(noopEffector).WithRetry(retrier)
+
+	// The first call uses up both tokens of the shared budget for its two retries.
+	err := failing.Do()
+	var exhausted *ErrRetriesExhausted
+	if err == nil || errors.As(err, &exhausted) {
+		t.Fatalf("Retrier.Retry() error = %v, want error of the last attempt", err)
+	}
+	if budget.Available() != 0 {
+		t.Fatalf("RetryBudget.Available() = %d, want 0", budget.Available())
+	}
+
+	// Without tokens no retry is made.
+	err = failing.Do()
+	if !errors.As(err, &exhausted) || !exhausted.BudgetExhausted || exhausted.Attempts != 1 {
+		t.Fatalf("Retrier.Retry() error = %v, want budget exhausted after 1 attempt", err)
+	}
+
+	// Two successful calls earn a retry.
+	for range 2 {
+		if err := succeeding.Do(); err != nil {
+			t.Fatalf("Retrier.Retry() error = %v", err)
+		}
+	}
+	if budget.Available() != 1 {
+		t.Errorf("RetryBudget.Available() = %d, want 1", budget.Available())
+	}
+
+	// The budget never exceeds its capacity.
+	for range 10 {
+		_ = succeeding.Do()
+	}
+	if budget.Available() != 2 {
+		t.Errorf("RetryBudget.Available() = %d, want 2", budget.Available())
+	}
+}
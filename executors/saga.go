@@ -0,0 +1,120 @@
+package executors
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Step is a step of a [Saga].
+type Step struct {
+	// Name identifies the step in errors. Defaults to the position of the step starting at 1.
+	Name string
+	// Action performs the step. A step without an action is skipped.
+	Action Effector
+	// Compensation undoes the action once a later step has failed. Nil if there is nothing to undo.
+	Compensation Effector
+}
+
+// ErrStep is the error of a step of a [Saga].
+type ErrStep struct {
+	// Step is the name of the step.
+	Step string
+	// Err is the error of the step.
+	Err error
+}
+
+// Error returns the error message.
+func (e *ErrStep) Error() string {
+	return fmt.Sprintf("step %q: %v", e.Step, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ErrStep) Unwrap() error {
+	return e.Err
+}
+
+// ErrSagaFailed is the error returned when a step of a [Saga] fails.
+type ErrSagaFailed struct {
+	// Failed is the error of the action that failed.
+	Failed *ErrStep
+	// Compensations are the errors of the compensations that failed in the order they ran.
+	// If it is empty, all completed steps have been undone.
+	Compensations []*ErrStep
+}
+
+// Error returns the error message.
+func (e *ErrSagaFailed) Error() string {
+	msg := "saga failed at " + e.Failed.Error()
+	if len(e.Compensations) == 0 {
+		return msg
+	}
+
+	errs := make([]string, len(e.Compensations))
+	for i, c := range e.Compensations {
+		errs[i] = c.Error()
+	}
+	return fmt.Sprintf("%s; %d compensations failed: %s", msg, len(e.Compensations), strings.Join(errs, "; "))
+}
+
+// Unwrap returns the error of the failed action followed by the errors of the failed compensations.
+func (e *ErrSagaFailed) Unwrap() []error {
+	errs := []error{e.Failed}
+	for _, c := range e.Compensations {
+		errs = append(errs, c)
+	}
+	return errs
+}
+
+// Saga returns an effector that runs the actions of the steps sequentially.
+// If an action fails, no further steps are run and the compensations of the completed steps
+// are run in reverse order. All compensations are run, even if some of them fail.
+// Steps without an action are skipped and never compensated.
+// The compensations are run with a context that isn't canceled with the one of the saga,
+// so they can undo the steps even if the saga failed because its context was canceled.
+//
+// The returned error is an [*ErrSagaFailed] with the failed step and the failed compensations.
+// Wrap a compensation with e.g. [Effector.WithRetry] to retry it.
+//
+// Safe to use concurrently.
+func Saga(steps ...Step) Effector {
+	return func(ctx context.Context) error {
+		for i, step := range steps {
+			if step.Action == nil {
+				continue
+			}
+			if err := step.Action(ctx); err != nil {
+				return &ErrSagaFailed{
+					Failed:        &ErrStep{Step: step.name(i), Err: err},
+					Compensations: compensate(context.WithoutCancel(ctx), steps[:i]),
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// compensate runs the compensations of the completed steps in reverse order and returns their errors.
+func compensate(ctx context.Context, completed []Step) []*ErrStep {
+	var errs []*ErrStep
+	for i := len(completed) - 1; i >= 0; i-- {
+		step := completed[i]
+		// A step without an action didn't do anything, so there is nothing to undo.
+		if step.Action == nil || step.Compensation == nil {
+			continue
+		}
+		if err := step.Compensation(ctx); err != nil {
+			errs = append(errs, &ErrStep{Step: step.name(i), Err: err})
+		}
+	}
+	return errs
+}
+
+// name returns the name of the step at the given index.
+func (s Step) name(i int) string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return strconv.Itoa(i + 1)
+}
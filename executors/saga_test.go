@@ -0,0 +1,146 @@
+package executors
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestSaga(t *testing.T) {
+	errAction := errors.New("action error")
+	errCompensation := errors.New("compensation error")
+
+	tests := []struct {
+		name              string
+		noActions         []string
+		failAction        string
+		failCompensations []string
+		wantLog           []string
+		wantFailed        string
+		wantCompensations []string
+	}{
+		{
+			name:    "all steps succeed",
+			wantLog: []string{"do reserve", "do charge", "do ship"},
+		},
+		{
+			name:       "first step fails",
+			failAction: "reserve",
+			wantLog:    []string{"do reserve"},
+			wantFailed: "reserve",
+		},
+		{
+			name:       "last step fails",
+			failAction: "ship",
+			wantLog:    []string{"do reserve", "do charge", "do ship", "undo charge", "undo reserve"},
+			wantFailed: "ship",
+		},
+		{
+			name:              "compensation fails",
+			failAction:        "ship",
+			failCompensations: []string{"charge"},
+			wantLog:           []string{"do reserve", "do charge", "do ship", "undo charge", "undo reserve"},
+			wantFailed:        "ship",
+			wantCompensations: []string{"charge"},
+		},
+		{
+			name:       "step without action is not compensated",
+			noActions:  []string{"charge"},
+			failAction: "ship",
+			wantLog:    []string{"do reserve", "do ship", "undo reserve"},
+			wantFailed: "ship",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var log []string
+			step := func(name string) Step {
+				if slices.Contains(tt.noActions, name) {
+					return Step{
+						Name: name,
+						Compensation: func(context.Context) error {
+							log = append(log, "undo "+name)
+							return nil
+						},
+					}
+				}
+				return Step{
+					Name: name,
+					Action: func(context.Context) error {
+						log = append(log, "do "+name)
+						if name == tt.failAction {
+							return errAction
+						}
+						return nil
+					},
+					Compensation: func(context.Context) error {
+						log = append(log, "undo "+name)
+						if slices.Contains(tt.failCompensations, name) {
+							return errCompensation
+						}
+						return nil
+					},
+				}
+			}
+
+			err := Saga(step("reserve"), step("charge"), step("ship")).Do()
+			if !slices.Equal(log, tt.wantLog) {
+				t.Errorf("Saga() log = %v, want %v", log, tt.wantLog)
+			}
+			if tt.wantFailed == "" {
+				if err != nil {
+					t.Errorf("Saga() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var sagaErr *ErrSagaFailed
+			if !errors.As(err, &sagaErr) {
+				t.Fatalf("Saga() error = %v, want %T", err, sagaErr)
+			}
+			if sagaErr.Failed.Step != tt.wantFailed || !errors.Is(err, errAction) {
+				t.Errorf("Saga() failed step = %v, want %q", sagaErr.Failed, tt.wantFailed)
+			}
+			var compensations []string
+			for _, c := range sagaErr.Compensations {
+				compensations = append(compensations, c.Step)
+			}
+			if !slices.Equal(compensations, tt.wantCompensations) {
+				t.Errorf("Saga() failed compensations = %v, want %v", compensations, tt.wantCompensations)
+			}
+			if len(tt.wantCompensations) > 0 && !errors.Is(err, errCompensation) {
+				t.Errorf("Saga() error = %v, want to wrap %v", err, errCompensation)
+			}
+		})
+	}
+}
+
+func TestSaga_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	compensated := false
+	err := Saga(
+		Step{
+			Action: func(context.Context) error { return nil },
+			Compensation: func(ctx context.Context) error {
+				compensated = ctx.Err() == nil
+				return nil
+			},
+		},
+		Step{
+			Action: func(ctx context.Context) error {
+				cancel()
+				return ctx.Err()
+			},
+		},
+	).Do(ctx)
+
+	var sagaErr *ErrSagaFailed
+	if !errors.As(err, &sagaErr) || sagaErr.Failed.Step != "2" {
+		t.Errorf("Saga() error = %v, want failure of step 2", err)
+	}
+	if !compensated {
+		t.Error("Saga() compensation got a canceled context")
+	}
+}
@@ -0,0 +1,213 @@
+package executors
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Schedule determines when a [Scheduler] runs its effector.
+type Schedule interface {
+	// Next returns the next run time after the given time.
+	// The zero time means that there are no further runs.
+	Next(after time.Time) time.Time
+}
+
+// Every returns a [Schedule] that runs at a fixed interval.
+// A non-positive interval never runs.
+func Every(interval time.Duration) Schedule {
+	return every(interval)
+}
+
+// every is the [Schedule] returned by [Every].
+type every time.Duration
+
+// Next returns the time one interval after the given time.
+func (e every) Next(after time.Time) time.Time {
+	if e <= 0 {
+		return time.Time{}
+	}
+	return after.Add(time.Duration(e))
+}
+
+// Overlap determines what a [Scheduler] does if a run is due while the previous run is still in progress.
+type Overlap int
+
+const (
+	// OverlapSkip skips the due run. This is the default.
+	OverlapSkip Overlap = iota
+	// OverlapQueue runs the due run after the previous run has finished.
+	OverlapQueue
+	// OverlapCancel cancels the context of the previous run and starts the due run once it has returned.
+	OverlapCancel
+)
+
+// SchedulerOptions are the options of a [Scheduler].
+type SchedulerOptions struct {
+	// Overlap determines what happens if a run is due while the previous run is still in progress. Defaults to [OverlapSkip].
+	Overlap Overlap
+	// Jitter delays every run by a random duration of up to Jitter, so multiple replicas don't run at the same time.
+	Jitter time.Duration
+	// Rand is the source of the jitter. Defaults to the global source of [math/rand/v2].
+	Rand Rand
+	// OnError is called with the error of every failed run.
+	OnError func(ctx context.Context, err error)
+	// Clock determines when runs are due. Defaults to [RealClock].
+	Clock Clock
+}
+
+// Scheduler runs an effector on a [Schedule].
+// The effector may be wrapped with any policy, e.g. [Effector.WithRetry], which then applies to every run.
+//
+// Example:
+//
+//	schedule, err := executors.Cron("*/5 * * * *")
+//	if err != nil {
+//		return err
+//	}
+//	scheduler := executors.NewScheduler(schedule, job.WithRetry(executors.DefaultRetrier), executors.SchedulerOptions{
+//		Jitter: 10 * time.Second,
+//	})
+//	err = scheduler.Run(ctx)
+type Scheduler struct {
+	// schedule determines the run times.
+	schedule Schedule
+	// effector is run at every run time.
+	effector Effector
+	// opts are the options of the scheduler.
+	opts SchedulerOptions
+
+	// mu protects the fields below.
+	mu sync.Mutex
+	// next is the time of the next run.
+	next time.Time
+	// running indicates that a run is in progress.
+	running bool
+	// pending is the number of queued runs.
+	pending int
+	// cancel cancels the context of the latest run.
+	cancel context.CancelFunc
+	// done is closed once the latest run has returned.
+	done chan struct{}
+}
+
+// NewScheduler creates a new [Scheduler] that runs the effector on the schedule.
+func NewScheduler(schedule Schedule, effector Effector, opts SchedulerOptions) *Scheduler {
+	if schedule == nil {
+		schedule = Every(0)
+	}
+	if effector == nil {
+		effector = noopEffector
+	}
+	opts.Rand = newLockedRand(opts.Rand)
+	opts.Clock = clockOrReal(opts.Clock)
+	return &Scheduler{schedule: schedule, effector: effector, opts: opts}
+}
+
+// Next returns the time of the next run including its jitter.
+// Returns the zero time if the scheduler isn't running or there are no further runs.
+func (s *Scheduler) Next() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.next
+}
+
+// Run runs the effector on the schedule until the context is done or the schedule has no further runs.
+// The runs receive a context derived from the given one. Once the context is done, no further runs are started
+// and Run returns the context error after all runs in progress have returned.
+// Run must not be called concurrently.
+func (s *Scheduler) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer s.setNext(time.Time{})
+
+	last := s.opts.Clock.Now()
+	for {
+		due := s.schedule.Next(last)
+		if due.IsZero() {
+			return nil
+		}
+		at := due.Add(between(s.opts.Rand, 0, s.opts.Jitter))
+		s.setNext(at)
+
+		if err := sleep(ctx, s.opts.Clock, at.Sub(s.opts.Clock.Now())); err != nil {
+			return err
+		}
+
+		// The next run is based on the due time to avoid drift, unless runs have been missed.
+		last = due
+		if now := s.opts.Clock.Now(); s.schedule.Next(due).Before(now) {
+			last = now
+		}
+		s.trigger(ctx, &wg)
+	}
+}
+
+// setNext sets the time of the next run.
+func (s *Scheduler) setNext(next time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.next = next
+}
+
+// trigger starts a run according to the overlap policy.
+func (s *Scheduler) trigger(ctx context.Context, wg *sync.WaitGroup) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch s.opts.Overlap {
+	case OverlapQueue:
+		if s.running {
+			s.pending++
+			return
+		}
+		s.running = true
+		wg.Go(func() {
+			for {
+				s.run(ctx)
+				s.mu.Lock()
+				if s.pending == 0 || ctx.Err() != nil {
+					s.running, s.pending = false, 0
+					s.mu.Unlock()
+					return
+				}
+				s.pending--
+				s.mu.Unlock()
+			}
+		})
+	case OverlapCancel:
+		if s.cancel != nil {
+			s.cancel()
+		}
+		previous := s.done
+		runCtx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		s.cancel, s.done = cancel, done
+		wg.Go(func() {
+			defer close(done)
+			defer cancel()
+			if previous != nil {
+				<-previous
+			}
+			s.run(runCtx)
+		})
+	default:
+		if s.running {
+			return
+		}
+		s.running = true
+		wg.Go(func() {
+			s.run(ctx)
+			s.mu.Lock()
+			s.running = false
+			s.mu.Unlock()
+		})
+	}
+}
+
+// run runs the effector once and reports its error. A panic is reported as [*PanicError].
+func (s *Scheduler) run(ctx context.Context) {
+	if err := Protector(s.effector)(ctx); err != nil && s.opts.OnError != nil {
+		s.opts.OnError(ctx, err)
+	}
+}
@@ -0,0 +1,174 @@
+package executors
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEvery(t *testing.T) {
+	from := time.Date(2026, time.March, 2, 10, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		interval time.Duration
+		want     time.Time
+	}{
+		{name: "positive interval", interval: time.Minute, want: from.Add(time.Minute)},
+		{name: "zero interval", interval: 0, want: time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Every(tt.interval).Next(from); !got.Equal(tt.want) {
+				t.Errorf("Every().Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// runTimes is a [Schedule] that runs at the given times.
+type runTimes []time.Time
+
+func (r runTimes) Next(after time.Time) time.Time {
+	for _, t := range r {
+		if t.After(after) {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// runsIn returns a [Schedule] with the given number of runs at the given interval starting now.
+func runsIn(n int, interval time.Duration) runTimes {
+	start := time.Now()
+	times := make(runTimes, n)
+	for i := range times {
+		times[i] = start.Add(time.Duration(i+1) * interval)
+	}
+	return times
+}
+
+func TestScheduler_Run(t *testing.T) {
+	tests := []struct {
+		name        string
+		overlap     Overlap
+		runDuration time.Duration
+		wantRuns    int32
+		wantCancels int32
+	}{
+		{
+			name:     "runs without overlap",
+			overlap:  OverlapSkip,
+			wantRuns: 4,
+		},
+		{
+			name:        "skips overlapping runs",
+			overlap:     OverlapSkip,
+			runDuration: 100 * time.Millisecond,
+			wantRuns:    1,
+		},
+		{
+			name:        "queues overlapping runs",
+			overlap:     OverlapQueue,
+			runDuration: 100 * time.Millisecond,
+			wantRuns:    4,
+		},
+		{
+			name:        "cancels overlapping runs",
+			overlap:     OverlapCancel,
+			runDuration: 100 * time.Millisecond,
+			wantRuns:    4,
+			wantCancels: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var runs, cancels atomic.Int32
+			effector := Effector(func(ctx context.Context) error {
+				runs.Add(1)
+				select {
+				case <-time.After(tt.runDuration):
+				case <-ctx.Done():
+					cancels.Add(1)
+				}
+				return nil
+			})
+
+			scheduler := effector.Schedule(runsIn(4, 20*time.Millisecond), SchedulerOptions{Overlap: tt.overlap})
+
+			if err := scheduler.Run(context.Background()); err != nil {
+				t.Fatalf("Scheduler.Run() error = %v", err)
+			}
+			if got := runs.Load(); got != tt.wantRuns {
+				t.Errorf("Scheduler.Run() runs = %d, want %d", got, tt.wantRuns)
+			}
+			if got := cancels.Load(); got != tt.wantCancels {
+				t.Errorf("Scheduler.Run() canceled runs = %d, want %d", got, tt.wantCancels)
+			}
+		})
+	}
+}
+
+func TestScheduler_Stop(t *testing.T) {
+	errTest := errors.New("test error")
+	var (
+		mu   sync.Mutex
+		errs []error
+	)
+	calls := 0
+	effector := Effector(func(context.Context) error {
+		calls++
+		if calls == 1 {
+			return errTest
+		}
+		return nil
+	}).WithRetry(Retrier{MaxRetries: 2, Backoff: ConstantBackoff(time.Millisecond)})
+
+	onError := func(_ context.Context, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, err)
+	}
+
+	scheduler := NewScheduler(Every(time.Hour), effector, SchedulerOptions{Jitter: time.Minute, OnError: onError})
+	if next := scheduler.Next(); !next.IsZero() {
+		t.Errorf("Scheduler.Next() = %v before run, want zero time", next)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	start := time.Now()
+	go func() { done <- scheduler.Run(ctx) }()
+
+	var next time.Time
+	for next.IsZero() && time.Since(start) < time.Second {
+		time.Sleep(time.Millisecond)
+		next = scheduler.Next()
+	}
+	if next.Before(start.Add(time.Hour)) || next.After(start.Add(time.Hour+time.Minute+time.Second)) {
+		t.Errorf("Scheduler.Next() = %v, want within jitter after %v", next, start.Add(time.Hour))
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Scheduler.Run() error = %v, want %v", err, context.Canceled)
+	}
+	if next := scheduler.Next(); !next.IsZero() {
+		t.Errorf("Scheduler.Next() = %v after stop, want zero time", next)
+	}
+
+	// A retried run only reports its final outcome.
+	scheduler = NewScheduler(runsIn(1, time.Millisecond), effector, SchedulerOptions{OnError: onError})
+	if err := scheduler.Run(context.Background()); err != nil {
+		t.Fatalf("Scheduler.Run() error = %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if calls != 2 || len(errs) != 0 {
+		t.Errorf("Scheduler.Run() calls = %d, errors = %v, want 2 calls without errors", calls, errs)
+	}
+}
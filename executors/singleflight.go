@@ -0,0 +1,137 @@
+package executors
+
+import (
+	"context"
+	"sync"
+)
+
+// FlightGroup collapses concurrent calls with the same key into one execution and shares its result.
+// The zero value is ready to use.
+//
+// The shared execution runs with a context that isn't canceled with the context of any caller,
+// so a caller that gives up doesn't cancel the execution for the others.
+// Once all callers have given up, the context of the execution is canceled.
+//
+// Example:
+//
+//	var group executors.FlightGroup[*User]
+//	fetch := group.Func(userIDFromContext, fetchUser)
+//	// Invalidate the in-flight call after an update, so later calls fetch the user again.
+//	group.Forget(id)
+type FlightGroup[T any] struct {
+	// mu protects the flights.
+	mu sync.Mutex
+	// flights are the in-flight executions by key.
+	flights map[string]*flight[T]
+}
+
+// flight is an in-flight execution of a [FlightGroup].
+type flight[T any] struct {
+	// done is closed once the execution has finished.
+	done chan struct{}
+	// value is the value of the execution.
+	value T
+	// err is the error of the execution.
+	err error
+	// waiters is the number of callers waiting for the execution.
+	waiters int
+	// cancel cancels the context of the execution.
+	cancel context.CancelFunc
+}
+
+// Do runs the func for the key unless an execution for the key is already in flight,
+// in which case it waits for that execution and returns its result.
+// If the context of the caller is done first, Do returns the context error without waiting for the execution.
+func (g *FlightGroup[T]) Do(ctx context.Context, key string, f Func[T]) (T, error) {
+	g.mu.Lock()
+	if g.flights == nil {
+		g.flights = map[string]*flight[T]{}
+	}
+	c, ok := g.flights[key]
+	if !ok {
+		runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		c = &flight[T]{done: make(chan struct{}), cancel: cancel}
+		g.flights[key] = c
+		go g.run(runCtx, key, c, f)
+	}
+	c.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return c.value, c.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		c.waiters--
+		if c.waiters == 0 {
+			// Nobody is interested in the result anymore.
+			c.cancel()
+			g.forget(key, c)
+		}
+		g.mu.Unlock()
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// run runs the execution of the flight and publishes its result.
+func (g *FlightGroup[T]) run(ctx context.Context, key string, c *flight[T], f Func[T]) {
+	defer c.cancel()
+	if f == nil {
+		f = func(context.Context) (v T, err error) { return v, err }
+	}
+	// A panic would crash the program, as nobody could recover it in this goroutine.
+	c.value, c.err = f.WithProtection()(ctx)
+
+	g.mu.Lock()
+	g.forget(key, c)
+	g.mu.Unlock()
+	close(c.done)
+}
+
+// Forget forgets the in-flight execution for the key, so later calls start a new execution
+// instead of waiting for the current one. Callers already waiting still get its result.
+func (g *FlightGroup[T]) Forget(key string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.flights, key)
+}
+
+// forget removes the flight of the key if it is still the current one.
+// The caller must hold the lock.
+func (g *FlightGroup[T]) forget(key string, c *flight[T]) {
+	if g.flights[key] == c {
+		delete(g.flights, key)
+	}
+}
+
+// Func returns a func that runs the given func through the group with the key derived by the key func.
+func (g *FlightGroup[T]) Func(keyFunc KeyFunc, f Func[T]) Func[T] {
+	keyFunc = keyFuncOrConstant(keyFunc)
+	return func(ctx context.Context) (T, error) {
+		return g.Do(ctx, keyFunc(ctx), f)
+	}
+}
+
+// Effector returns an effector that runs the given effector through the group with the key derived by the key func.
+func (g *FlightGroup[T]) Effector(keyFunc KeyFunc, effector Effector) Effector {
+	if effector == nil {
+		return noopEffector
+	}
+	f := Func[T](func(ctx context.Context) (v T, err error) {
+		return v, effector(ctx)
+	})
+	return g.Func(keyFunc, f).Effector()
+}
+
+// SingleFlight returns an effector that collapses concurrent calls with the same key into one execution
+// and shares its error. Use a [FlightGroup] to forget keys.
+func SingleFlight(keyFunc KeyFunc, effector Effector) Effector {
+	return new(FlightGroup[struct{}]).Effector(keyFunc, effector)
+}
+
+// SingleFlightFunc returns a func that collapses concurrent calls with the same key into one execution
+// and shares its value and error. Use a [FlightGroup] to forget keys.
+func SingleFlightFunc[T any](keyFunc KeyFunc, f Func[T]) Func[T] {
+	return new(FlightGroup[T]).Func(keyFunc, f)
+}
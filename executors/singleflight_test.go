@@ -0,0 +1,150 @@
+package executors
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSingleFlightFunc(t *testing.T) {
+	tests := []struct {
+		name      string
+		keys      []string
+		wantCalls int32
+	}{
+		{
+			name:      "same key is executed once",
+			keys:      []string{"a", "a", "a", "a"},
+			wantCalls: 1,
+		},
+		{
+			name:      "different keys are executed separately",
+			keys:      []string{"a", "b", "a", "b"},
+			wantCalls: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			release := make(chan struct{})
+			f := SingleFlightFunc(tenantOf, func(ctx context.Context) (string, error) {
+				calls.Add(1)
+				<-release
+				return "value of " + tenantOf(ctx), nil
+			})
+
+			var wg sync.WaitGroup
+			results := make([]Result[string], len(tt.keys))
+			for i, key := range tt.keys {
+				wg.Go(func() {
+					v, err := f(withTenant(key))
+					results[i] = Result[string]{Value: v, Err: err}
+				})
+			}
+			// Give all callers the chance to join the flights before they finish.
+			time.Sleep(20 * time.Millisecond)
+			close(release)
+			wg.Wait()
+
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("SingleFlightFunc() calls = %d, want %d", got, tt.wantCalls)
+			}
+			for i, r := range results {
+				if want := "value of " + tt.keys[i]; r.Value != want || r.Err != nil {
+					t.Errorf("SingleFlightFunc() = %v, %v, want %v, nil", r.Value, r.Err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSingleFlight_Cancel(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	var sharedErr atomic.Value
+	effector := SingleFlight(nil, func(ctx context.Context) error {
+		calls.Add(1)
+		select {
+		case <-release:
+			return nil
+		case <-ctx.Done():
+			sharedErr.Store(ctx.Err())
+			return ctx.Err()
+		}
+	})
+
+	canceled, cancel := context.WithCancel(context.Background())
+	first := effector.Go(canceled)
+	second := effector.Go(context.Background())
+	time.Sleep(20 * time.Millisecond)
+
+	cancel()
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Errorf("SingleFlight() error of canceled caller = %v, want %v", err, context.Canceled)
+	}
+
+	close(release)
+	if err := <-second; err != nil {
+		t.Errorf("SingleFlight() error of other caller = %v, want nil", err)
+	}
+	if calls.Load() != 1 || sharedErr.Load() != nil {
+		t.Errorf("SingleFlight() calls = %d, shared error = %v, want one uncanceled call", calls.Load(), sharedErr.Load())
+	}
+}
+
+func TestSingleFlight_AllCanceled(t *testing.T) {
+	canceled := make(chan struct{})
+	effector := SingleFlight(nil, func(ctx context.Context) error {
+		<-ctx.Done()
+		close(canceled)
+		return ctx.Err()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := effector.Do(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("SingleFlight() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Error("SingleFlight() didn't cancel the execution after all callers gave up")
+	}
+}
+
+func TestFlightGroup_Forget(t *testing.T) {
+	var group FlightGroup[int]
+	var calls atomic.Int32
+	release := make(chan struct{})
+	f := group.Func(nil, func(context.Context) (int, error) {
+		n := calls.Add(1)
+		<-release
+		return int(n), nil
+	})
+
+	first := f.Go()
+	time.Sleep(20 * time.Millisecond)
+	group.Forget("")
+	second := f.Go()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	a, b := <-first, <-second
+	if calls.Load() != 2 || a.Value == b.Value {
+		t.Errorf("FlightGroup.Forget() calls = %d, values = %d, %d, want separate executions", calls.Load(), a.Value, b.Value)
+	}
+}
+
+func TestSingleFlight_Panic(t *testing.T) {
+	effector := SingleFlight(nil, func(context.Context) error {
+		panic("test")
+	})
+	if err := effector.Do(); err == nil {
+		t.Error("SingleFlight() error = nil, want recovered panic")
+	}
+}
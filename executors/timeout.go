@@ -2,21 +2,32 @@ package executors
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+// errTimeouterExceeded is the cause of the context canceled by a [Timeouter].
+var errTimeouterExceeded = errors.New("timeouter deadline exceeded")
+
 // Timeouter returns an effector that stops calling the task if it takes longer than the specified timeout.
-func Timeouter(timeout time.Duration, effector Effector) Effector {
+func Timeouter(timeout time.Duration, effector Effector, opts ...Option) Effector {
 	if effector == nil {
 		return noopEffector
 	}
+	o := newOptions(opts)
 
 	return func(ctx context.Context) error {
 		if timeout <= 0 {
+			o.observer.OnTimeout(ctx, timeout)
 			return context.DeadlineExceeded
 		}
-		ctx, cancel := context.WithTimeout(ctx, timeout)
+		tctx, cancel := withTimeoutCause(ctx, o.clock, timeout, errTimeouterExceeded)
 		defer cancel()
-		return effector(ctx)
+
+		err := effector(tctx)
+		if errors.Is(context.Cause(tctx), errTimeouterExceeded) {
+			o.observer.OnTimeout(ctx, timeout)
+		}
+		return err
 	}
 }
@@ -0,0 +1,209 @@
+package executors
+
+import (
+	"time"
+)
+
+// Outcome is the outcome of a call made through a circuit breaker.
+type Outcome struct {
+	// Time is the time the call finished.
+	Time time.Time
+	// Duration is the amount of time the call took.
+	Duration time.Duration
+	// Failed is true if the call counts as a failure.
+	Failed bool
+}
+
+// TripPolicy decides when a closed circuit should open based on the outcomes of past calls.
+//
+// A trip policy holds state and must not be shared between breakers.
+// Its methods are called with the lock of the breaker held, so implementations don't need to be safe for concurrent use.
+type TripPolicy interface {
+	// Record records the outcome of a call and reports whether the circuit should open.
+	Record(o Outcome) bool
+	// Reset discards all recorded outcomes. It is called whenever the circuit closes.
+	Reset()
+}
+
+// WindowOptions configures when a sliding window [TripPolicy] trips.
+type WindowOptions struct {
+	// MinCalls is the minimum number of calls in the window before the circuit may open.
+	// Defaults to 1.
+	MinCalls int
+	// FailureRatio is the ratio of failed calls in the window, in the range (0, 1], at which the circuit opens.
+	// A value of zero disables tripping on failures.
+	FailureRatio float64
+	// SlowCallRatio is the ratio of slow calls in the window, in the range (0, 1], at which the circuit opens.
+	// A value of zero disables tripping on slow calls.
+	SlowCallRatio float64
+	// SlowCallDuration is the duration above which a call is considered slow.
+	SlowCallDuration time.Duration
+}
+
+// exceeded reports whether the given counts exceed the configured thresholds.
+func (o *WindowOptions) exceeded(c counts) bool {
+	if c.calls == 0 || c.calls < max(o.MinCalls, 1) {
+		return false
+	}
+	if o.FailureRatio > 0 && float64(c.failures)/float64(c.calls) >= o.FailureRatio {
+		return true
+	}
+	return o.SlowCallRatio > 0 && float64(c.slow)/float64(c.calls) >= o.SlowCallRatio
+}
+
+// counts are the aggregated outcomes of calls.
+type counts struct {
+	calls    int
+	failures int
+	slow     int
+}
+
+// add adds the outcome to the counts.
+func (c *counts) add(o Outcome, slowCallDuration time.Duration) {
+	c.calls++
+	if o.Failed {
+		c.failures++
+	}
+	if slowCallDuration > 0 && o.Duration >= slowCallDuration {
+		c.slow++
+	}
+}
+
+// sub removes the aggregated counts of another bucket.
+func (c *counts) sub(other counts) {
+	c.calls -= other.calls
+	c.failures -= other.failures
+	c.slow -= other.slow
+}
+
+// ConsecutiveFailures returns a [TripPolicy] that opens the circuit after n consecutive failures.
+// This is the policy used by [Breaker] if no other policy is configured.
+func ConsecutiveFailures(n int) TripPolicy {
+	return &consecutiveFailures{threshold: max(n, 1)}
+}
+
+// consecutiveFailures is a [TripPolicy] that counts consecutive failures.
+type consecutiveFailures struct {
+	// threshold is the number of consecutive failures that trips the circuit.
+	threshold int
+	// failures is the current number of consecutive failures.
+	failures int
+}
+
+// Record records the outcome of a call and reports whether the circuit should open.
+func (p *consecutiveFailures) Record(o Outcome) bool {
+	if !o.Failed {
+		p.failures = 0
+		return false
+	}
+	p.failures++
+	return p.failures >= p.threshold
+}
+
+// Reset discards all recorded outcomes.
+func (p *consecutiveFailures) Reset() {
+	p.failures = 0
+}
+
+// CountWindow returns a [TripPolicy] that evaluates the outcomes of the last size calls.
+func CountWindow(size int, opts WindowOptions) TripPolicy {
+	size = max(size, 1)
+	return &countWindow{opts: opts, outcomes: make([]Outcome, 0, size), size: size}
+}
+
+// countWindow is a [TripPolicy] backed by a ring buffer of the last calls.
+type countWindow struct {
+	// opts are the thresholds of the window.
+	opts WindowOptions
+	// outcomes is the ring buffer of recorded outcomes.
+	outcomes []Outcome
+	// size is the capacity of the ring buffer.
+	size int
+	// next is the index the next outcome is written to once the buffer is full.
+	next int
+	// total are the aggregated counts of all outcomes in the buffer.
+	total counts
+}
+
+// Record records the outcome of a call and reports whether the circuit should open.
+func (p *countWindow) Record(o Outcome) bool {
+	if len(p.outcomes) < p.size {
+		p.outcomes = append(p.outcomes, o)
+	} else {
+		var evicted counts
+		evicted.add(p.outcomes[p.next], p.opts.SlowCallDuration)
+		p.total.sub(evicted)
+		p.outcomes[p.next] = o
+		p.next = (p.next + 1) % p.size
+	}
+	p.total.add(o, p.opts.SlowCallDuration)
+	return p.opts.exceeded(p.total)
+}
+
+// Reset discards all recorded outcomes.
+func (p *countWindow) Reset() {
+	p.outcomes = p.outcomes[:0]
+	p.next = 0
+	p.total = counts{}
+}
+
+// timeWindowBuckets is the number of buckets a time window is divided into.
+const timeWindowBuckets = 10
+
+// TimeWindow returns a [TripPolicy] that evaluates the outcomes of all calls that finished within the given duration.
+// The window slides in steps of a tenth of its duration.
+func TimeWindow(length time.Duration, opts WindowOptions) TripPolicy {
+	width := max(length/timeWindowBuckets, 1)
+	return &timeWindow{opts: opts, width: width}
+}
+
+// timeWindow is a [TripPolicy] that aggregates outcomes into buckets of fixed width.
+type timeWindow struct {
+	// opts are the thresholds of the window.
+	opts WindowOptions
+	// width is the duration covered by a single bucket.
+	width time.Duration
+	// buckets are the aggregated counts per bucket.
+	buckets [timeWindowBuckets]counts
+	// head is the index of the bucket covering the current time.
+	head int
+	// headStart is the start time of the head bucket.
+	headStart time.Time
+	// total are the aggregated counts of all buckets.
+	total counts
+}
+
+// Record records the outcome of a call and reports whether the circuit should open.
+func (p *timeWindow) Record(o Outcome) bool {
+	p.advance(o.Time)
+	p.buckets[p.head].add(o, p.opts.SlowCallDuration)
+	p.total.add(o, p.opts.SlowCallDuration)
+	return p.opts.exceeded(p.total)
+}
+
+// advance moves the head of the window to the bucket covering the given time and expires outdated buckets.
+func (p *timeWindow) advance(now time.Time) {
+	if p.headStart.IsZero() {
+		p.headStart = now
+		return
+	}
+
+	steps := int(now.Sub(p.headStart) / p.width)
+	if steps <= 0 {
+		return
+	}
+	for i := 0; i < min(steps, timeWindowBuckets); i++ {
+		p.head = (p.head + 1) % timeWindowBuckets
+		p.total.sub(p.buckets[p.head])
+		p.buckets[p.head] = counts{}
+	}
+	p.headStart = p.headStart.Add(time.Duration(steps) * p.width)
+}
+
+// Reset discards all recorded outcomes.
+func (p *timeWindow) Reset() {
+	p.buckets = [timeWindowBuckets]counts{}
+	p.head = 0
+	p.headStart = time.Time{}
+	p.total = counts{}
+}
@@ -0,0 +1,123 @@
+package executors
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTripPolicy_Record(t *testing.T) {
+	start := time.Now()
+	failure := func(offset time.Duration) Outcome { return Outcome{Time: start.Add(offset), Failed: true} }
+	success := func(offset time.Duration) Outcome { return Outcome{Time: start.Add(offset)} }
+	slow := func(offset time.Duration) Outcome { return Outcome{Time: start.Add(offset), Duration: time.Second} }
+
+	tests := []struct {
+		name     string
+		policy   TripPolicy
+		outcomes []Outcome
+		want     bool
+	}{
+		{
+			name:     "consecutive failures below threshold",
+			policy:   ConsecutiveFailures(3),
+			outcomes: []Outcome{failure(0), failure(0), success(0), failure(0), failure(0)},
+			want:     false,
+		},
+		{
+			name:     "consecutive failures at threshold",
+			policy:   ConsecutiveFailures(3),
+			outcomes: []Outcome{success(0), failure(0), failure(0), failure(0)},
+			want:     true,
+		},
+		{
+			name:     "count window below min calls",
+			policy:   CountWindow(10, WindowOptions{MinCalls: 5, FailureRatio: 0.5}),
+			outcomes: []Outcome{failure(0), failure(0), failure(0), failure(0)},
+			want:     false,
+		},
+		{
+			name:     "count window trips on failure ratio with mixed traffic",
+			policy:   CountWindow(5, WindowOptions{MinCalls: 5, FailureRatio: 0.4}),
+			outcomes: []Outcome{failure(0), success(0), failure(0), success(0), success(0)},
+			want:     true,
+		},
+		{
+			name:     "count window evicts old outcomes",
+			policy:   CountWindow(3, WindowOptions{MinCalls: 3, FailureRatio: 0.5}),
+			outcomes: []Outcome{failure(0), failure(0), success(0), success(0), success(0)},
+			want:     false,
+		},
+		{
+			name:     "count window trips on slow call ratio",
+			policy:   CountWindow(4, WindowOptions{MinCalls: 4, SlowCallRatio: 0.5, SlowCallDuration: 500 * time.Millisecond}),
+			outcomes: []Outcome{slow(0), success(0), slow(0), success(0)},
+			want:     true,
+		},
+		{
+			name:     "count window ignores slow calls without slow call duration",
+			policy:   CountWindow(4, WindowOptions{MinCalls: 4, SlowCallRatio: 0.5}),
+			outcomes: []Outcome{slow(0), success(0), slow(0), success(0)},
+			want:     false,
+		},
+		{
+			name:     "time window trips on failure ratio",
+			policy:   TimeWindow(time.Second, WindowOptions{MinCalls: 4, FailureRatio: 0.5}),
+			outcomes: []Outcome{failure(0), success(100 * time.Millisecond), failure(200 * time.Millisecond), success(300 * time.Millisecond)},
+			want:     true,
+		},
+		{
+			name:     "time window expires old outcomes",
+			policy:   TimeWindow(time.Second, WindowOptions{MinCalls: 2, FailureRatio: 0.5}),
+			outcomes: []Outcome{failure(0), failure(100 * time.Millisecond), success(2 * time.Second), success(2100 * time.Millisecond), failure(2200 * time.Millisecond)},
+			want:     false,
+		},
+		{
+			name:     "time window slides partially",
+			policy:   TimeWindow(time.Second, WindowOptions{MinCalls: 3, FailureRatio: 0.6}),
+			outcomes: []Outcome{success(0), failure(500 * time.Millisecond), failure(900 * time.Millisecond), failure(1050 * time.Millisecond)},
+			want:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got bool
+			for _, o := range tt.outcomes {
+				got = tt.policy.Record(o)
+			}
+			if got != tt.want {
+				t.Errorf("TripPolicy.Record() = %v, want %v", got, tt.want)
+			}
+
+			tt.policy.Reset()
+			if tt.policy.Record(success(0)) {
+				t.Errorf("TripPolicy.Record() after Reset() = true, want false")
+			}
+		})
+	}
+}
+
+func TestBreaker_TripPolicy(t *testing.T) {
+	b := NewBreaker(BreakerOptions{
+		TripPolicy:   CountWindow(10, WindowOptions{MinCalls: 10, FailureRatio: 0.4}),
+		ResetTimeout: time.Hour,
+	})
+
+	for i := range 10 {
+		err := b.Wrap(func(ctx context.Context) error {
+			if i%5 < 2 {
+				return errors.New("failed")
+			}
+			return nil
+		}).Do()
+		if i < 9 && errors.Is(err, ErrCircuitOpen{}) {
+			t.Fatalf("Breaker opened after %d calls, want 10", i+1)
+		}
+	}
+
+	if got := b.State(); got != StateOpen {
+		t.Errorf("Breaker.State() = %v, want %v", got, StateOpen)
+	}
+}
@@ -1,9 +1,7 @@
 package rest
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -13,6 +11,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/lvlcn-t/go-kit/executors"
 	"golang.org/x/time/rate"
 )
 
@@ -102,6 +101,23 @@ type Request struct {
 	// ResponseHandler is the handler to be called when the response is received.
 	// If not set, it will decode the response body into the provided response object.
 	ResponseHandler ResponseHandler
+	// Retry configures the retries of the request. If nil, the request is made once.
+	Retry *RetryOptions
+	// Breaker is the circuit breaker the request is made through. If nil, no circuit breaker is used.
+	Breaker *executors.Breaker
+	// Codec encodes the payload and decodes the response. If nil, the payload is encoded as JSON
+	// and the response is decoded with the codec registered for its Content-Type.
+	Codec Codec
+	// Middlewares wrap every attempt of the request, the first middleware being the outermost.
+	Middlewares []Middleware
+}
+
+// codec returns the codec that encodes the payload of the request.
+func (r *Request) codec() Codec {
+	if r.Codec == nil {
+		return JSONCodec
+	}
+	return r.Codec
 }
 
 // RequestOption is a function that modifies a request.
@@ -133,6 +149,8 @@ type restClient struct {
 	client *http.Client
 	// limiter is the rate limiter used for requests.
 	limiter *rate.Limiter
+	// opts are the request options applied to all requests before the options of the request.
+	opts []RequestOption
 	// wg is the wait group used to track pending requests.
 	wg sync.WaitGroup
 }
@@ -151,7 +169,8 @@ func New(baseURL string, timeout ...time.Duration) (Client, error) {
 
 // NewWithClient creates a new rest client with the given base URL and [http.Client].
 // If the client is nil, it will create a new client with the [DefaultTransport] and [DefaultTimeout].
-func NewWithClient(baseURL string, client *http.Client) (Client, error) {
+// The options are applied to all requests before the options of the request, e.g. [WithRetry] or [WithCircuitBreaker].
+func NewWithClient(baseURL string, client *http.Client, opts ...RequestOption) (Client, error) {
 	if _, err := url.Parse(baseURL); err != nil {
 		return nil, fmt.Errorf("invalid base URL: %w", err)
 	}
@@ -164,6 +183,7 @@ func NewWithClient(baseURL string, client *http.Client) (Client, error) {
 		baseURL: baseURL,
 		client:  client,
 		limiter: rate.NewLimiter(maxRequestRate, maxRequestBurst),
+		opts:    opts,
 	}, nil
 }
 
@@ -194,35 +214,39 @@ func (r *restClient) Do(ctx context.Context, endpoint *Endpoint, payload, respon
 
 // do is the implementation of the [Client].Do method that makes the request to the given endpoint.
 func (r *restClient) do(ctx context.Context, endpoint *Endpoint, payload, response any, opts []RequestOption) (int, error) {
-	if err := r.limiter.Wait(ctx); err != nil {
-		return 0, ErrRateLimitExceeded
-	}
-
-	body := io.Reader(http.NoBody)
-	if payload != nil {
-		data, err := json.Marshal(payload)
-		if err != nil {
-			return 0, fmt.Errorf("failed to marshal payload: %w", err)
-		}
-		body = bytes.NewBuffer(data)
-	}
-
 	u, err := endpoint.Build(r.baseURL)
 	if err != nil {
 		return 0, fmt.Errorf("failed to compile endpoint: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, endpoint.Method, u, body)
+	req, err := http.NewRequestWithContext(ctx, endpoint.Method, u, http.NoBody)
 	if err != nil {
 		return 0, fmt.Errorf("failed to create request: %w", err)
 	}
-	req.Header.Set("Content-Type", "application/json")
 
-	request := &Request{Http: req, Delay: 0, ResponseHandler: handleResponse(response)}
+	request := &Request{Http: req, Delay: 0}
+	request.ResponseHandler = handleResponse(response, request)
+	for _, opt := range r.opts {
+		opt(request)
+	}
 	for _, opt := range opts {
 		opt(request)
 	}
 
+	codec := request.codec()
+	contentType := codec.ContentType()
+	if payload != nil {
+		var body io.Reader
+		body, contentType, err = codec.Encode(payload)
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal payload: %w", err)
+		}
+		setBody(request.Http, body)
+	}
+	if request.Http.Header.Get("Content-Type") == "" {
+		request.Http.Header.Set("Content-Type", contentType)
+	}
+
 	if request.Delay > 0 {
 		select {
 		case <-time.After(request.Delay):
@@ -233,9 +257,9 @@ func (r *restClient) do(ctx context.Context, endpoint *Endpoint, payload, respon
 
 	r.wg.Add(1)
 	defer r.wg.Done()
-	resp, err := r.client.Do(request.Http) // #nosec G704 // URL is controlled by the caller and should be validated before being passed to the client.
+	resp, err := r.roundTrip(ctx, request)
 	if err != nil {
-		return 0, fmt.Errorf("failed to make request: %w", err)
+		return 0, err
 	}
 	defer func() {
 		err = errors.Join(err, resp.Body.Close())
@@ -295,7 +319,8 @@ func WithHeader(key, value string) RequestOption {
 	}
 }
 
-// WithBearer is a request option that sets a bearer token for the request
+// WithBearer is a request option that sets a bearer token for the request.
+// Use [WithTokenSource] for tokens that expire.
 func WithBearer(token string) RequestOption {
 	return WithHeader("Authorization", fmt.Sprintf("Bearer %s", token))
 }
@@ -359,13 +384,21 @@ func defaultTransport() *http.Transport {
 }
 
 // handleResponse returns a function that decodes the response body into the given response object.
-func handleResponse(response any) ResponseHandler {
+// The body is decoded with the codec of the request if set, with the codec registered for the Content-Type of the response otherwise.
+func handleResponse(response any, request *Request) ResponseHandler {
 	return func(resp *http.Response) error {
 		if response == nil || resp.StatusCode >= http.StatusBadRequest {
 			return nil
 		}
 
-		if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		codec := request.Codec
+		if codec == nil {
+			var ok bool
+			if codec, ok = CodecFor(resp.Header.Get("Content-Type")); !ok {
+				codec = JSONCodec
+			}
+		}
+		if err := codec.Decode(resp.Body, response); err != nil {
 			return &ErrDecodingResponse{Err: err}
 		}
 		return nil
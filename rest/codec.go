@@ -0,0 +1,417 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Codec encodes request payloads and decodes response bodies of a media type.
+//
+// Implementations must be safe for concurrent use.
+type Codec interface {
+	// ContentType returns the media type of the encoded payloads, e.g. "application/json".
+	ContentType() string
+	// Encode encodes the payload into a request body and returns it with its content type.
+	// The content type may differ from the one returned by ContentType, e.g. by a multipart boundary.
+	// Bodies that are a [bytes.Buffer], [bytes.Reader] or [strings.Reader] can be rebuilt for retries.
+	Encode(payload any) (body io.Reader, contentType string, err error)
+	// Decode decodes the response body into the response object.
+	Decode(body io.Reader, response any) error
+}
+
+var (
+	// JSONCodec encodes and decodes JSON. It is the default codec.
+	JSONCodec Codec = jsonCodec{}
+	// XMLCodec encodes and decodes XML.
+	XMLCodec Codec = xmlCodec{}
+	// FormCodec encodes and decodes URL encoded forms from and into [url.Values], map[string]string and map[string][]string.
+	FormCodec Codec = formCodec{}
+	// MultipartCodec encodes a [MultipartForm] as multipart/form-data. Decoding is not supported.
+	MultipartCodec Codec = multipartCodec{}
+	// ProtobufCodec passes protobuf messages through as bytes.
+	// It encodes []byte and types with a Marshal() ([]byte, error) method
+	// and decodes into *[]byte and types with an Unmarshal([]byte) error method,
+	// so it works with generated messages without depending on a protobuf library.
+	ProtobufCodec Codec = protobufCodec{}
+	// RawCodec passes bodies through as they are.
+	// It encodes an [io.Reader], []byte and string and decodes into an [io.Writer], *[]byte and *string.
+	// Bodies encoded from an [io.Reader] other than the ones listed at [Codec] are not retried.
+	RawCodec Codec = rawCodec{}
+)
+
+// ErrUnsupportedType is the error returned when a [Codec] can't encode or decode a type.
+type ErrUnsupportedType struct {
+	// Codec is the content type of the codec.
+	Codec string
+	// Type is the unsupported type.
+	Type string
+}
+
+// Error returns the error message.
+func (e *ErrUnsupportedType) Error() string {
+	return fmt.Sprintf("codec %q doesn't support type %s", e.Codec, e.Type)
+}
+
+// newErrUnsupportedType creates a new [ErrUnsupportedType] for the codec and the value.
+func newErrUnsupportedType(c Codec, v any) *ErrUnsupportedType {
+	return &ErrUnsupportedType{Codec: c.ContentType(), Type: fmt.Sprintf("%T", v)}
+}
+
+// codecs are the registered codecs by media type.
+var codecs = struct {
+	sync.RWMutex
+	m map[string]Codec
+}{m: map[string]Codec{
+	"application/json":                  JSONCodec,
+	"application/xml":                   XMLCodec,
+	"text/xml":                          XMLCodec,
+	"application/x-www-form-urlencoded": FormCodec,
+	"multipart/form-data":               MultipartCodec,
+	"application/x-protobuf":            ProtobufCodec,
+	"application/protobuf":              ProtobufCodec,
+	"application/octet-stream":          RawCodec,
+}}
+
+// RegisterCodec registers the codec for the media type, replacing the codec registered before.
+// Responses with the media type as Content-Type are decoded with the codec.
+func RegisterCodec(mediaType string, codec Codec) {
+	codecs.Lock()
+	defer codecs.Unlock()
+	codecs.m[strings.ToLower(mediaType)] = codec
+}
+
+// CodecFor returns the codec registered for the media type of the content type.
+// Media types with a structured syntax suffix like "application/problem+json" fall back to the codec of the suffix.
+func CodecFor(contentType string) (Codec, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, false
+	}
+
+	codecs.RLock()
+	defer codecs.RUnlock()
+	if c, ok := codecs.m[mediaType]; ok {
+		return c, true
+	}
+	if i := strings.LastIndexByte(mediaType, '+'); i >= 0 {
+		c, ok := codecs.m["application/"+mediaType[i+1:]]
+		return c, ok
+	}
+	return nil, false
+}
+
+// WithCodec is a request option that encodes the payload and decodes the response with the codec.
+// Without it, the payload is encoded as JSON and the response is decoded with the codec registered for its Content-Type,
+// falling back to JSON.
+func WithCodec(codec Codec) RequestOption {
+	return func(r *Request) {
+		r.Codec = codec
+	}
+}
+
+// jsonCodec is the [Codec] of [JSONCodec].
+type jsonCodec struct{}
+
+// ContentType returns the media type of JSON.
+func (jsonCodec) ContentType() string { return "application/json" }
+
+// Encode marshals the payload to JSON.
+func (c jsonCodec) Encode(payload any) (io.Reader, string, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, "", err
+	}
+	return bytes.NewReader(data), c.ContentType(), nil
+}
+
+// Decode unmarshals the JSON body into the response.
+func (jsonCodec) Decode(body io.Reader, response any) error {
+	return json.NewDecoder(body).Decode(response)
+}
+
+// xmlCodec is the [Codec] of [XMLCodec].
+type xmlCodec struct{}
+
+// ContentType returns the media type of XML.
+func (xmlCodec) ContentType() string { return "application/xml" }
+
+// Encode marshals the payload to XML.
+func (c xmlCodec) Encode(payload any) (io.Reader, string, error) {
+	data, err := xml.Marshal(payload)
+	if err != nil {
+		return nil, "", err
+	}
+	return bytes.NewReader(data), c.ContentType(), nil
+}
+
+// Decode unmarshals the XML body into the response.
+func (xmlCodec) Decode(body io.Reader, response any) error {
+	return xml.NewDecoder(body).Decode(response)
+}
+
+// formCodec is the [Codec] of [FormCodec].
+type formCodec struct{}
+
+// ContentType returns the media type of URL encoded forms.
+func (formCodec) ContentType() string { return "application/x-www-form-urlencoded" }
+
+// Encode encodes the form values.
+func (c formCodec) Encode(payload any) (io.Reader, string, error) {
+	values := url.Values{}
+	switch p := payload.(type) {
+	case url.Values:
+		values = p
+	case map[string][]string:
+		values = p
+	case map[string]string:
+		for key, value := range p {
+			values.Set(key, value)
+		}
+	default:
+		return nil, "", newErrUnsupportedType(c, payload)
+	}
+	return strings.NewReader(values.Encode()), c.ContentType(), nil
+}
+
+// Decode parses the form values of the body into the response.
+func (c formCodec) Decode(body io.Reader, response any) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	values, err := url.ParseQuery(string(data))
+	if err != nil {
+		return err
+	}
+
+	switch r := response.(type) {
+	case *url.Values:
+		*r = values
+	case *map[string][]string:
+		*r = values
+	case *map[string]string:
+		*r = make(map[string]string, len(values))
+		for key := range values {
+			(*r)[key] = values.Get(key)
+		}
+	default:
+		return newErrUnsupportedType(c, response)
+	}
+	return nil
+}
+
+// MultipartForm is the payload of the [MultipartCodec].
+type MultipartForm struct {
+	// Values are the plain form fields.
+	Values url.Values
+	// Files are the file parts of the form.
+	Files []FormFile
+}
+
+// FormFile is a file part of a [MultipartForm].
+type FormFile struct {
+	// Field is the name of the form field.
+	Field string
+	// FileName is the name of the file.
+	FileName string
+	// ContentType is the content type of the file. Defaults to "application/octet-stream".
+	ContentType string
+	// Content is the content of the file.
+	Content io.Reader
+}
+
+// multipartCodec is the [Codec] of [MultipartCodec].
+type multipartCodec struct{}
+
+// ContentType returns the media type of multipart forms.
+func (multipartCodec) ContentType() string { return "multipart/form-data" }
+
+// Encode writes the form into a multipart body.
+// The whole form is buffered, so the body can be rebuilt for retries.
+func (c multipartCodec) Encode(payload any) (io.Reader, string, error) {
+	var form *MultipartForm
+	switch p := payload.(type) {
+	case MultipartForm:
+		form = &p
+	case *MultipartForm:
+		form = p
+	default:
+		return nil, "", newErrUnsupportedType(c, payload)
+	}
+
+	buf := &bytes.Buffer{}
+	w := multipart.NewWriter(buf)
+	for key, values := range form.Values {
+		for _, value := range values {
+			if err := w.WriteField(key, value); err != nil {
+				return nil, "", err
+			}
+		}
+	}
+	for _, file := range form.Files {
+		if err := writeFile(w, file); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, "", err
+	}
+	return buf, w.FormDataContentType(), nil
+}
+
+// Decode is not supported for multipart bodies.
+func (c multipartCodec) Decode(_ io.Reader, response any) error {
+	return newErrUnsupportedType(c, response)
+}
+
+// writeFile writes the file as part of the multipart writer.
+func writeFile(w *multipart.Writer, file FormFile) error {
+	contentType := file.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", mime.FormatMediaType("form-data", map[string]string{
+		"name":     file.Field,
+		"filename": file.FileName,
+	}))
+	header.Set("Content-Type", contentType)
+
+	part, err := w.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	if file.Content == nil {
+		return nil
+	}
+	_, err = io.Copy(part, file.Content)
+	return err
+}
+
+// protobufCodec is the [Codec] of [ProtobufCodec].
+type protobufCodec struct{}
+
+// ContentType returns the media type of protobuf messages.
+func (protobufCodec) ContentType() string { return "application/x-protobuf" }
+
+// Encode marshals the message.
+func (c protobufCodec) Encode(payload any) (io.Reader, string, error) {
+	switch p := payload.(type) {
+	case []byte:
+		return bytes.NewReader(p), c.ContentType(), nil
+	case interface{ Marshal() ([]byte, error) }:
+		data, err := p.Marshal()
+		if err != nil {
+			return nil, "", err
+		}
+		return bytes.NewReader(data), c.ContentType(), nil
+	default:
+		return nil, "", newErrUnsupportedType(c, payload)
+	}
+}
+
+// Decode unmarshals the body into the message.
+func (c protobufCodec) Decode(body io.Reader, response any) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	switch r := response.(type) {
+	case *[]byte:
+		*r = data
+		return nil
+	case interface{ Unmarshal([]byte) error }:
+		return r.Unmarshal(data)
+	default:
+		return newErrUnsupportedType(c, response)
+	}
+}
+
+// rawCodec is the [Codec] of [RawCodec].
+type rawCodec struct{}
+
+// ContentType returns the media type of arbitrary bytes.
+func (rawCodec) ContentType() string { return "application/octet-stream" }
+
+// Encode passes the payload through.
+func (c rawCodec) Encode(payload any) (io.Reader, string, error) {
+	switch p := payload.(type) {
+	case io.Reader:
+		return p, c.ContentType(), nil
+	case []byte:
+		return bytes.NewReader(p), c.ContentType(), nil
+	case string:
+		return strings.NewReader(p), c.ContentType(), nil
+	default:
+		return nil, "", newErrUnsupportedType(c, payload)
+	}
+}
+
+// Decode copies the body into the response.
+func (c rawCodec) Decode(body io.Reader, response any) error {
+	switch r := response.(type) {
+	case io.Writer:
+		_, err := io.Copy(r, body)
+		return err
+	case *[]byte:
+		data, err := io.ReadAll(body)
+		*r = data
+		return err
+	case *string:
+		data, err := io.ReadAll(body)
+		*r = string(data)
+		return err
+	default:
+		return newErrUnsupportedType(c, response)
+	}
+}
+
+// setBody sets the body of the request.
+// Like [http.NewRequest], it allows rebuilding in-memory bodies for retries and redirects.
+func setBody(req *http.Request, body io.Reader) {
+	if body == nil || body == http.NoBody {
+		req.Body, req.GetBody, req.ContentLength = http.NoBody, nil, 0
+		return
+	}
+
+	switch b := body.(type) {
+	case *bytes.Buffer:
+		data := b.Bytes()
+		req.ContentLength = int64(len(data))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(data)), nil
+		}
+	case *bytes.Reader:
+		snapshot := *b
+		req.ContentLength = int64(b.Len())
+		req.GetBody = func() (io.ReadCloser, error) {
+			r := snapshot
+			return io.NopCloser(&r), nil
+		}
+	case *strings.Reader:
+		snapshot := *b
+		req.ContentLength = int64(b.Len())
+		req.GetBody = func() (io.ReadCloser, error) {
+			r := snapshot
+			return io.NopCloser(&r), nil
+		}
+	default:
+		req.ContentLength, req.GetBody = 0, nil
+	}
+
+	rc, ok := body.(io.ReadCloser)
+	if !ok {
+		rc = io.NopCloser(body)
+	}
+	req.Body = rc
+}
@@ -0,0 +1,296 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+)
+
+// message is a stand-in for a generated protobuf message.
+type message struct {
+	data []byte
+}
+
+func (m *message) Marshal() ([]byte, error) { return m.data, nil }
+
+func (m *message) Unmarshal(data []byte) error {
+	m.data = data
+	return nil
+}
+
+func TestCodec_RoundTrip(t *testing.T) {
+	type item struct {
+		XMLName xml.Name `json:"-" xml:"item"`
+		ID      int      `json:"id" xml:"id"`
+		Name    string   `json:"name" xml:"name"`
+	}
+
+	tests := []struct {
+		name    string
+		codec   Codec
+		payload any
+		decoded func() any
+		want    any
+	}{
+		{
+			name:    "json",
+			codec:   JSONCodec,
+			payload: item{ID: 1, Name: "Resource"},
+			decoded: func() any { return &item{} },
+			want:    &item{ID: 1, Name: "Resource"},
+		},
+		{
+			name:    "xml",
+			codec:   XMLCodec,
+			payload: item{ID: 1, Name: "Resource"},
+			decoded: func() any { return &item{} },
+			want:    &item{XMLName: xml.Name{Local: "item"}, ID: 1, Name: "Resource"},
+		},
+		{
+			name:    "form from map",
+			codec:   FormCodec,
+			payload: map[string]string{"name": "Resource"},
+			decoded: func() any { return &url.Values{} },
+			want:    &url.Values{"name": []string{"Resource"}},
+		},
+		{
+			name:    "form from values",
+			codec:   FormCodec,
+			payload: url.Values{"tag": []string{"a", "b"}},
+			decoded: func() any { return &map[string]string{} },
+			want:    &map[string]string{"tag": "a"},
+		},
+		{
+			name:    "protobuf message",
+			codec:   ProtobufCodec,
+			payload: &message{data: []byte{0x08, 0x01}},
+			decoded: func() any { return &message{} },
+			want:    &message{data: []byte{0x08, 0x01}},
+		},
+		{
+			name:    "protobuf bytes",
+			codec:   ProtobufCodec,
+			payload: []byte{0x08, 0x01},
+			decoded: func() any { return &[]byte{} },
+			want:    &[]byte{0x08, 0x01},
+		},
+		{
+			name:    "raw reader",
+			codec:   RawCodec,
+			payload: strings.NewReader("raw"),
+			decoded: func() any { return new(string) },
+			want:    func() *string { s := "raw"; return &s }(),
+		},
+		{
+			name:    "raw writer",
+			codec:   RawCodec,
+			payload: []byte("raw"),
+			decoded: func() any { return &bytes.Buffer{} },
+			want:    bytes.NewBufferString("raw"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, contentType, err := tt.codec.Encode(tt.payload)
+			if err != nil {
+				t.Fatalf("Codec.Encode() error = %v", err)
+			}
+			if contentType != tt.codec.ContentType() {
+				t.Errorf("Codec.Encode() content type = %q, want %q", contentType, tt.codec.ContentType())
+			}
+
+			got := tt.decoded()
+			if err := tt.codec.Decode(body, got); err != nil {
+				t.Fatalf("Codec.Decode() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Codec.Decode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCodec_UnsupportedType(t *testing.T) {
+	tests := []struct {
+		name  string
+		codec Codec
+	}{
+		{name: "form", codec: FormCodec},
+		{name: "multipart", codec: MultipartCodec},
+		{name: "protobuf", codec: ProtobufCodec},
+		{name: "raw", codec: RawCodec},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var target *ErrUnsupportedType
+			if _, _, err := tt.codec.Encode(42); !errors.As(err, &target) {
+				t.Errorf("Codec.Encode() error = %v, want %T", err, target)
+			}
+			if err := tt.codec.Decode(strings.NewReader(""), new(int)); !errors.As(err, &target) {
+				t.Errorf("Codec.Decode() error = %v, want %T", err, target)
+			}
+		})
+	}
+}
+
+func TestMultipartCodec_Encode(t *testing.T) {
+	form := MultipartForm{
+		Values: url.Values{"name": []string{"Resource"}},
+		Files:  []FormFile{{Field: "file", FileName: "data.txt", ContentType: "text/plain", Content: strings.NewReader("content")}},
+	}
+	body, contentType, err := MultipartCodec.Encode(&form)
+	if err != nil {
+		t.Fatalf("MultipartCodec.Encode() error = %v", err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != "multipart/form-data" {
+		t.Fatalf("MultipartCodec.Encode() content type = %q, error = %v", contentType, err)
+	}
+	parsed, err := multipart.NewReader(body, params["boundary"]).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatalf("failed to read form: %v", err)
+	}
+	if got := parsed.Value["name"]; len(got) != 1 || got[0] != "Resource" {
+		t.Errorf("form value name = %v, want [Resource]", got)
+	}
+	files := parsed.File["file"]
+	if len(files) != 1 || files[0].Filename != "data.txt" || files[0].Header.Get("Content-Type") != "text/plain" {
+		t.Fatalf("form file = %v, want data.txt with text/plain", files)
+	}
+	f, err := files[0].Open()
+	if err != nil {
+		t.Fatalf("failed to open form file: %v", err)
+	}
+	defer f.Close()
+	if content, _ := io.ReadAll(f); string(content) != "content" {
+		t.Errorf("form file content = %q, want %q", content, "content")
+	}
+}
+
+func TestCodecFor(t *testing.T) {
+	custom := rawCodec{}
+	RegisterCodec("Application/Vnd.Custom", custom)
+
+	tests := []struct {
+		name        string
+		contentType string
+		want        Codec
+		wantOk      bool
+	}{
+		{name: "json", contentType: "application/json", want: JSONCodec, wantOk: true},
+		{name: "json with charset", contentType: "application/json; charset=utf-8", want: JSONCodec, wantOk: true},
+		{name: "problem json", contentType: "application/problem+json", want: JSONCodec, wantOk: true},
+		{name: "text xml", contentType: "text/xml", want: XMLCodec, wantOk: true},
+		{name: "atom xml", contentType: "application/atom+xml", want: XMLCodec, wantOk: true},
+		{name: "registered", contentType: "application/vnd.custom", want: custom, wantOk: true},
+		{name: "unknown", contentType: "text/html", wantOk: false},
+		{name: "unknown suffix", contentType: "application/vnd.api+yaml", wantOk: false},
+		{name: "empty", contentType: "", wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := CodecFor(tt.contentType)
+			if ok != tt.wantOk || got != tt.want {
+				t.Errorf("CodecFor() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestClient_Do_Codec(t *testing.T) {
+	type item struct {
+		XMLName xml.Name `xml:"item"`
+		Name    string   `xml:"name"`
+	}
+
+	tests := []struct {
+		name            string
+		payload         any
+		opts            []RequestOption
+		respContentType string
+		respBody        string
+		wantContentType string
+		wantBody        string
+		want            item
+		wantErr         bool
+	}{
+		{
+			name:            "codec option",
+			payload:         item{Name: "Request"},
+			opts:            []RequestOption{WithCodec(XMLCodec)},
+			respBody:        "<item><name>Response</name></item>",
+			wantContentType: "application/xml",
+			wantBody:        "<item><name>Request</name></item>",
+			want:            item{XMLName: xml.Name{Local: "item"}, Name: "Response"},
+		},
+		{
+			name:            "response content type",
+			payload:         map[string]string{"name": "Request"},
+			respContentType: "application/xml; charset=utf-8",
+			respBody:        "<item><name>Response</name></item>",
+			wantContentType: "application/json",
+			wantBody:        `{"name":"Request"}`,
+			want:            item{XMLName: xml.Name{Local: "item"}, Name: "Response"},
+		},
+		{
+			name:            "codec option takes precedence",
+			opts:            []RequestOption{WithCodec(XMLCodec)},
+			respContentType: "application/json",
+			respBody:        `{"name":"Response"}`,
+			wantContentType: "application/xml",
+			wantErr:         true,
+		},
+		{
+			name:            "content type header is kept",
+			payload:         "raw",
+			opts:            []RequestOption{WithCodec(RawCodec), WithHeader("Content-Type", "text/csv")},
+			respContentType: "application/xml",
+			respBody:        "<item><name>Response</name></item>",
+			wantContentType: "text/csv",
+			wantBody:        "raw",
+			wantErr:         true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := httpmock.NewMockTransport()
+			transport.RegisterResponder(http.MethodPut, "https://example.com/resource", func(req *http.Request) (*http.Response, error) {
+				if got := req.Header.Get("Content-Type"); got != tt.wantContentType {
+					t.Errorf("request Content-Type = %q, want %q", got, tt.wantContentType)
+				}
+				if body, _ := io.ReadAll(req.Body); string(body) != tt.wantBody {
+					t.Errorf("request body = %q, want %q", body, tt.wantBody)
+				}
+				resp := httpmock.NewStringResponse(http.StatusOK, tt.respBody)
+				resp.Header.Set("Content-Type", tt.respContentType)
+				return resp, nil
+			})
+			c := newMockClient(t, transport)
+
+			var got item
+			_, err := c.Do(context.Background(), Put("/resource"), tt.payload, &got, tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Client.Do() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Client.Do() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,7 +4,14 @@ go 1.26
 
 require (
 	github.com/jarcoal/httpmock v1.4.1
+	github.com/lvlcn-t/go-kit/executors v0.4.0
+	golang.org/x/oauth2 v0.36.0
 	golang.org/x/time v0.15.0
 )
 
-require github.com/davecgh/go-spew v1.1.2-0.20180830191138-d8f796af33cc // indirect
+require (
+	github.com/davecgh/go-spew v1.1.2-0.20180830191138-d8f796af33cc // indirect
+	golang.org/x/sync v0.20.0 // indirect
+)
+
+replace github.com/lvlcn-t/go-kit/executors => ../executors
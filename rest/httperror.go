@@ -0,0 +1,147 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+)
+
+// MaxErrorBodySize is the maximum number of bytes of a response body kept by an [HTTPError].
+const MaxErrorBodySize = 64 << 10
+
+// HTTPError is the error returned for responses with a status code of 400 or higher if [WithHTTPError] is used.
+//
+// Example:
+//
+//	_, err := client.Do(ctx, endpoint, nil, &resp, rest.WithHTTPError(nil))
+//	var httpErr *rest.HTTPError
+//	if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound {
+//		// Handle not found
+//	}
+type HTTPError struct {
+	// StatusCode is the status code of the response.
+	StatusCode int
+	// Status is the status line of the response, e.g. "404 Not Found".
+	Status string
+	// Header are the headers of the response.
+	Header http.Header
+	// Body is a snapshot of the response body of up to [MaxErrorBodySize] bytes.
+	Body []byte
+	// Truncated is true if the response body was longer than the snapshot.
+	Truncated bool
+	// Problem is the decoded body if the response is an RFC 7807 "application/problem+json" document.
+	Problem *Problem
+	// Decoded is the error object the body was decoded into, if one was requested and decoding succeeded.
+	Decoded any
+}
+
+// Error returns the error message.
+func (e *HTTPError) Error() string {
+	msg := fmt.Sprintf("unexpected response status %s", e.Status)
+	if e.Problem != nil {
+		switch {
+		case e.Problem.Detail != "":
+			msg += ": " + e.Problem.Detail
+		case e.Problem.Title != "":
+			msg += ": " + e.Problem.Title
+		}
+	}
+	return msg
+}
+
+// Problem is an RFC 7807 problem details object.
+type Problem struct {
+	// Type is a URI reference that identifies the problem type.
+	Type string `json:"type,omitempty"`
+	// Title is a short, human-readable summary of the problem type.
+	Title string `json:"title,omitempty"`
+	// Status is the status code generated by the origin server.
+	Status int `json:"status,omitempty"`
+	// Detail is a human-readable explanation specific to this occurrence of the problem.
+	Detail string `json:"detail,omitempty"`
+	// Instance is a URI reference that identifies the specific occurrence of the problem.
+	Instance string `json:"instance,omitempty"`
+	// Extensions are all other members of the problem object.
+	Extensions map[string]any `json:"-"`
+}
+
+// UnmarshalJSON decodes the problem and collects all unknown members as extensions.
+func (p *Problem) UnmarshalJSON(data []byte) error {
+	type problem Problem
+	if err := json.Unmarshal(data, (*problem)(p)); err != nil {
+		return err
+	}
+
+	var members map[string]any
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+	for _, key := range []string{"type", "title", "status", "detail", "instance"} {
+		delete(members, key)
+	}
+	if len(members) > 0 {
+		p.Extensions = members
+	}
+	return nil
+}
+
+// WithHTTPError is a request option that returns an [*HTTPError] for responses with a status code of 400 or higher
+// instead of returning no error. Use it as option of [NewWithClient] to enable it for all requests of a client.
+//
+// If newDecoded is not nil, the body of error responses is decoded into the object it returns,
+// using the codec registered for the Content-Type of the response. The object is available as HTTPError.Decoded.
+func WithHTTPError(newDecoded func() any) RequestOption {
+	return WithErrorHandler(func(resp *http.Response) error {
+		var decoded any
+		if newDecoded != nil {
+			decoded = newDecoded()
+		}
+		return NewHTTPError(resp, decoded)
+	}, nil)
+}
+
+// NewHTTPError creates a new [HTTPError] from the response and reads a snapshot of its body.
+// If decoded is not nil, the body is decoded into it with the codec registered for the Content-Type of the response,
+// falling back to JSON.
+func NewHTTPError(resp *http.Response, decoded any) *HTTPError {
+	e := &HTTPError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Header:     resp.Header,
+	}
+	if e.Status == "" {
+		e.Status = fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	if resp.Body == nil {
+		return e
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, MaxErrorBodySize+1))
+	e.Truncated = len(body) > MaxErrorBodySize
+	e.Body = body[:min(len(body), MaxErrorBodySize)]
+	if e.Truncated {
+		// A truncated body can't be decoded.
+		return e
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil && mediaType == "application/problem+json" {
+		problem := &Problem{}
+		if json.Unmarshal(e.Body, problem) == nil {
+			e.Problem = problem
+		}
+	}
+	if decoded != nil {
+		codec, ok := CodecFor(contentType)
+		if !ok {
+			codec = JSONCodec
+		}
+		if codec.Decode(bytes.NewReader(e.Body), decoded) == nil {
+			e.Decoded = decoded
+		}
+	}
+	return e
+}
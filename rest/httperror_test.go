@@ -0,0 +1,164 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func TestNewHTTPError(t *testing.T) {
+	type apiError struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+
+	tests := []struct {
+		name          string
+		status        int
+		contentType   string
+		body          string
+		decoded       any
+		wantProblem   *Problem
+		wantDecoded   any
+		wantTruncated bool
+		wantMsg       string
+	}{
+		{
+			name:    "plain body",
+			status:  http.StatusNotFound,
+			body:    "not found",
+			wantMsg: "unexpected response status 404 Not Found",
+		},
+		{
+			name:        "problem json",
+			status:      http.StatusBadRequest,
+			contentType: "application/problem+json",
+			body:        `{"type":"https://example.com/invalid","title":"Invalid request","status":400,"detail":"name is required","field":"name"}`,
+			wantProblem: &Problem{
+				Type:       "https://example.com/invalid",
+				Title:      "Invalid request",
+				Status:     http.StatusBadRequest,
+				Detail:     "name is required",
+				Extensions: map[string]any{"field": "name"},
+			},
+			wantMsg: "unexpected response status 400 Bad Request: name is required",
+		},
+		{
+			name:        "decoded error object",
+			status:      http.StatusConflict,
+			contentType: "application/json",
+			body:        `{"code":"conflict","message":"resource exists"}`,
+			decoded:     &apiError{},
+			wantDecoded: &apiError{Code: "conflict", Message: "resource exists"},
+			wantMsg:     "unexpected response status 409 Conflict",
+		},
+		{
+			name:        "undecodable error object",
+			status:      http.StatusInternalServerError,
+			contentType: "text/html",
+			body:        "<html></html>",
+			decoded:     &apiError{},
+			wantMsg:     "unexpected response status 500 Internal Server Error",
+		},
+		{
+			name:          "truncated body",
+			status:        http.StatusBadGateway,
+			contentType:   "application/problem+json",
+			body:          strings.Repeat("x", MaxErrorBodySize+1),
+			wantTruncated: true,
+			wantMsg:       "unexpected response status 502 Bad Gateway",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := httpmock.NewStringResponse(tt.status, tt.body)
+			resp.Status = ""
+			if tt.contentType != "" {
+				resp.Header.Set("Content-Type", tt.contentType)
+			}
+
+			err := NewHTTPError(resp, tt.decoded)
+			if err.StatusCode != tt.status {
+				t.Errorf("NewHTTPError() StatusCode = %d, want %d", err.StatusCode, tt.status)
+			}
+			if err.Error() != tt.wantMsg {
+				t.Errorf("HTTPError.Error() = %q, want %q", err.Error(), tt.wantMsg)
+			}
+			if err.Truncated != tt.wantTruncated || len(err.Body) > MaxErrorBodySize {
+				t.Errorf("NewHTTPError() Truncated = %v with %d bytes, want %v", err.Truncated, len(err.Body), tt.wantTruncated)
+			}
+			if !tt.wantTruncated && string(err.Body) != tt.body {
+				t.Errorf("NewHTTPError() Body = %q, want %q", err.Body, tt.body)
+			}
+			if !reflect.DeepEqual(err.Problem, tt.wantProblem) {
+				t.Errorf("NewHTTPError() Problem = %+v, want %+v", err.Problem, tt.wantProblem)
+			}
+			if !reflect.DeepEqual(err.Decoded, tt.wantDecoded) {
+				t.Errorf("NewHTTPError() Decoded = %+v, want %+v", err.Decoded, tt.wantDecoded)
+			}
+		})
+	}
+}
+
+func TestClient_Do_HTTPError(t *testing.T) {
+	tests := []struct {
+		name       string
+		clientOpts []RequestOption
+		opts       []RequestOption
+		status     int
+		wantErr    bool
+	}{
+		{
+			name:    "disabled by default",
+			status:  http.StatusNotFound,
+			wantErr: false,
+		},
+		{
+			name:    "request option",
+			opts:    []RequestOption{WithHTTPError(nil)},
+			status:  http.StatusNotFound,
+			wantErr: true,
+		},
+		{
+			name:       "client option",
+			clientOpts: []RequestOption{WithHTTPError(nil)},
+			status:     http.StatusServiceUnavailable,
+			wantErr:    true,
+		},
+		{
+			name:    "success",
+			opts:    []RequestOption{WithHTTPError(nil)},
+			status:  http.StatusOK,
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := httpmock.NewMockTransport()
+			transport.RegisterResponder(http.MethodGet, "https://example.com/resource",
+				httpmock.NewStringResponder(tt.status, `{"id":1,"name":"Resource"}`))
+			c := newMockClient(t, transport, tt.clientOpts...)
+
+			var got response
+			status, err := c.Do(context.Background(), Get("/resource"), nil, &got, tt.opts...)
+			if status != tt.status {
+				t.Errorf("Client.Do() status = %d, want %d", status, tt.status)
+			}
+
+			var httpErr *HTTPError
+			if errors.As(err, &httpErr) != tt.wantErr {
+				t.Fatalf("Client.Do() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && (httpErr.StatusCode != tt.status || string(httpErr.Body) != `{"id":1,"name":"Resource"}`) {
+				t.Errorf("Client.Do() HTTPError = %+v", httpErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package rest
+
+import "net/http"
+
+// Doer makes HTTP requests. It is implemented by [http.Client].
+type Doer interface {
+	// Do sends the request and returns the response.
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// DoerFunc is an adapter to use a function as [Doer].
+type DoerFunc func(req *http.Request) (*http.Response, error)
+
+// Do calls the function.
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps a [Doer] to run logic around the round trip of a request, e.g. logging, metrics or request signing.
+// It runs for every attempt of a request, so a retried request passes it multiple times.
+//
+// Example:
+//
+//	signing := func(next rest.Doer) rest.Doer {
+//		return rest.DoerFunc(func(req *http.Request) (*http.Response, error) {
+//			req = req.Clone(req.Context())
+//			req.Header.Set("X-Signature", sign(req))
+//			return next.Do(req)
+//		})
+//	}
+//	client, err := rest.NewWithClient("https://api.example.com", nil, rest.WithMiddleware(signing))
+type Middleware func(next Doer) Doer
+
+// WithMiddleware is a request option that adds the middlewares to the request.
+// Use it as option of [NewWithClient] to add the middlewares to all requests of a client.
+// Middlewares added first are the outermost, so the ones of the client run before the ones of the request.
+func WithMiddleware(mw ...Middleware) RequestOption {
+	return func(r *Request) {
+		r.Middlewares = append(r.Middlewares, mw...)
+	}
+}
+
+// chain wraps the doer with the middlewares, the first middleware being the outermost.
+func chain(doer Doer, mw []Middleware) Doer {
+	for i := len(mw) - 1; i >= 0; i-- {
+		if mw[i] != nil {
+			doer = mw[i](doer)
+		}
+	}
+	return doer
+}
@@ -0,0 +1,48 @@
+// Package middleware provides common [rest.Middleware] for the [rest.Client].
+// It is a module of its own, so the rest module doesn't depend on the logging, metrics and tracing libraries it uses.
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/lvlcn-t/go-kit/rest"
+	"github.com/lvlcn-t/loggerhead/logger"
+)
+
+// Logging returns a [rest.Middleware] that logs every request with the logger of the request's context.
+// The query of the URL is redacted, since it may contain credentials.
+func Logging() rest.Middleware {
+	return func(next rest.Doer) rest.Doer {
+		return rest.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			ctx := req.Context()
+			log := logger.FromContext(ctx).With("method", req.Method, "url", redactedURL(req))
+
+			start := time.Now()
+			resp, err := next.Do(req)
+			duration := time.Since(start)
+			if err != nil {
+				log.ErrorContext(ctx, "Request failed", "duration", duration, "error", err)
+				return resp, err
+			}
+
+			if resp.StatusCode >= http.StatusInternalServerError {
+				log.ErrorContext(ctx, "Request completed", "status", resp.StatusCode, "duration", duration)
+				return resp, nil
+			}
+			log.InfoContext(ctx, "Request completed", "status", resp.StatusCode, "duration", duration)
+			return resp, nil
+		})
+	}
+}
+
+// redactedURL returns the URL of the request without its query and with a redacted password.
+func redactedURL(req *http.Request) string {
+	if req.URL == nil {
+		return ""
+	}
+	u := *req.URL
+	u.RawQuery = ""
+	u.Fragment = ""
+	return u.Redacted()
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/lvlcn-t/go-kit/rest"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var _ prometheus.Collector = (*Metrics)(nil)
+
+// Metrics records the requests of a [rest.Client] as Prometheus metrics.
+// It implements [prometheus.Collector] and has to be registered with a registry.
+//
+// Example:
+//
+//	metrics := middleware.NewMetrics("myapp", "payments_api")
+//	registry.MustRegister(metrics)
+//	client, err := rest.NewWithClient("https://api.example.com", nil, rest.WithMiddleware(metrics.Middleware()))
+type Metrics struct {
+	// requests counts the requests by method and status code.
+	requests *prometheus.CounterVec
+	// durations observes the duration of the requests by method.
+	durations *prometheus.HistogramVec
+	// inFlight is the number of running requests.
+	inFlight prometheus.Gauge
+}
+
+// NewMetrics creates a new [Metrics] with metrics in the given namespace and subsystem.
+// Use different subsystems to distinguish the metrics of multiple clients registered with the same registry.
+func NewMetrics(namespace, subsystem string) *Metrics {
+	return &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "http_client_requests_total",
+			Help:      "Total number of HTTP requests by method and status code. Failed requests have the code \"error\".",
+		}, []string{"method", "code"}),
+		durations: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "http_client_request_duration_seconds",
+			Help:      "Duration of HTTP requests in seconds.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "http_client_requests_in_flight",
+			Help:      "Number of running HTTP requests.",
+		}),
+	}
+}
+
+// Middleware returns the [rest.Middleware] that records the requests.
+func (m *Metrics) Middleware() rest.Middleware {
+	return func(next rest.Doer) rest.Doer {
+		return rest.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			m.inFlight.Inc()
+			defer m.inFlight.Dec()
+
+			start := time.Now()
+			resp, err := next.Do(req)
+			m.durations.WithLabelValues(req.Method).Observe(time.Since(start).Seconds())
+
+			code := "error"
+			if err == nil {
+				code = strconv.Itoa(resp.StatusCode)
+			}
+			m.requests.WithLabelValues(req.Method, code).Inc()
+			return resp, err
+		})
+	}
+}
+
+// Describe sends the descriptors of all metrics to the channel.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.requests.Describe(ch)
+	m.durations.Describe(ch)
+	m.inFlight.Describe(ch)
+}
+
+// Collect sends all metrics to the channel.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.requests.Collect(ch)
+	m.durations.Collect(ch)
+	m.inFlight.Collect(ch)
+}
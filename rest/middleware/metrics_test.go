@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics(t *testing.T) {
+	metrics := NewMetrics("test", "client")
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(metrics)
+
+	requests := []struct {
+		method string
+		status int
+		err    error
+	}{
+		{method: http.MethodGet, status: http.StatusOK},
+		{method: http.MethodGet, status: http.StatusOK},
+		{method: http.MethodPost, status: http.StatusServiceUnavailable},
+		{method: http.MethodGet, err: errors.New("connection reset")},
+	}
+	for _, r := range requests {
+		req, err := http.NewRequest(r.method, "https://example.com/resource", http.NoBody)
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+		_, _ = metrics.Middleware()(respond(r.status, r.err)).Do(req)
+	}
+
+	want := `
+# HELP test_client_http_client_requests_total Total number of HTTP requests by method and status code. Failed requests have the code "error".
+# TYPE test_client_http_client_requests_total counter
+test_client_http_client_requests_total{code="200",method="GET"} 2
+test_client_http_client_requests_total{code="503",method="POST"} 1
+test_client_http_client_requests_total{code="error",method="GET"} 1
+# HELP test_client_http_client_requests_in_flight Number of running HTTP requests.
+# TYPE test_client_http_client_requests_in_flight gauge
+test_client_http_client_requests_in_flight 0
+`
+	err := testutil.GatherAndCompare(registry, strings.NewReader(want),
+		"test_client_http_client_requests_total", "test_client_http_client_requests_in_flight")
+	if err != nil {
+		t.Errorf("Metrics mismatch: %v", err)
+	}
+	if got := testutil.CollectAndCount(metrics, "test_client_http_client_request_duration_seconds"); got != 2 {
+		t.Errorf("duration series = %d, want 2", got)
+	}
+}
@@ -0,0 +1,147 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/lvlcn-t/go-kit/rest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestTracing(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		err        error
+		wantStatus codes.Code
+	}{
+		{name: "success", status: http.StatusOK, wantStatus: codes.Unset},
+		{name: "client error", status: http.StatusNotFound, wantStatus: codes.Unset},
+		{name: "server error", status: http.StatusBadGateway, wantStatus: codes.Error},
+		{name: "transport error", err: errors.New("connection reset"), wantStatus: codes.Error},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &spanRecorder{}
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "https://example.com/resource", http.NoBody)
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+
+			var propagated trace.SpanContext
+			next := respond(tt.status, tt.err)
+			capture := func(req *http.Request) (*http.Response, error) {
+				ctx := propagation.TraceContext{}.Extract(context.Background(), propagation.HeaderCarrier(req.Header))
+				propagated = trace.SpanContextFromContext(ctx)
+				return next.Do(req)
+			}
+			_, _ = Tracing(provider, propagation.TraceContext{})(rest.DoerFunc(capture)).Do(req)
+
+			if len(provider.spans) != 1 {
+				t.Fatalf("Tracing() started %d spans, want 1", len(provider.spans))
+			}
+			span := provider.spans[0]
+			if !span.ended {
+				t.Error("Tracing() didn't end the span")
+			}
+			if span.kind != trace.SpanKindClient {
+				t.Errorf("span kind = %v, want %v", span.kind, trace.SpanKindClient)
+			}
+			if span.status != tt.wantStatus {
+				t.Errorf("span status = %v, want %v", span.status, tt.wantStatus)
+			}
+			if propagated.SpanID() != span.SpanContext().SpanID() {
+				t.Errorf("propagated span = %v, want %v", propagated.SpanID(), span.SpanContext().SpanID())
+			}
+			if req.Header.Get("traceparent") != "" {
+				t.Error("Tracing() modified the headers of the original request")
+			}
+			if tt.err == nil && !hasAttribute(span.attrs, attribute.Int("http.response.status_code", tt.status)) {
+				t.Errorf("span attributes = %v, want status code %d", span.attrs, tt.status)
+			}
+		})
+	}
+}
+
+// hasAttribute reports whether the attributes contain the attribute.
+func hasAttribute(attrs []attribute.KeyValue, want attribute.KeyValue) bool {
+	for _, attr := range attrs {
+		if attr == want {
+			return true
+		}
+	}
+	return false
+}
+
+// spanRecorder is a [trace.TracerProvider] that records the spans started by its tracers.
+type spanRecorder struct {
+	noop.TracerProvider
+	// spans are the started spans.
+	spans []*recordedSpan
+}
+
+// Tracer returns a tracer that records its spans.
+func (r *spanRecorder) Tracer(string, ...trace.TracerOption) trace.Tracer {
+	return &recordingTracer{recorder: r}
+}
+
+// recordingTracer is a [trace.Tracer] that records its spans in the recorder.
+type recordingTracer struct {
+	noop.Tracer
+	// recorder records the spans.
+	recorder *spanRecorder
+}
+
+// Start starts a new recorded span with a valid span context.
+func (t *recordingTracer) Start(ctx context.Context, _ string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	cfg := trace.NewSpanStartConfig(opts...)
+	id := byte(len(t.recorder.spans) + 1)
+	span := &recordedSpan{
+		sc: trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    trace.TraceID{id},
+			SpanID:     trace.SpanID{id},
+			TraceFlags: trace.FlagsSampled,
+		}),
+		kind:  cfg.SpanKind(),
+		attrs: cfg.Attributes(),
+	}
+	t.recorder.spans = append(t.recorder.spans, span)
+	return trace.ContextWithSpan(ctx, span), span
+}
+
+// recordedSpan is a [trace.Span] that records its kind, status and attributes.
+type recordedSpan struct {
+	noop.Span
+	// sc is the span context of the span.
+	sc trace.SpanContext
+	// kind is the kind of the span.
+	kind trace.SpanKind
+	// status is the status code of the span.
+	status codes.Code
+	// attrs are the attributes of the span.
+	attrs []attribute.KeyValue
+	// ended is true once the span has been ended.
+	ended bool
+}
+
+// SpanContext returns the span context of the span.
+func (s *recordedSpan) SpanContext() trace.SpanContext { return s.sc }
+
+// IsRecording returns true until the span has been ended.
+func (s *recordedSpan) IsRecording() bool { return !s.ended }
+
+// SetStatus records the status code.
+func (s *recordedSpan) SetStatus(code codes.Code, _ string) { s.status = code }
+
+// SetAttributes records the attributes.
+func (s *recordedSpan) SetAttributes(kv ...attribute.KeyValue) { s.attrs = append(s.attrs, kv...) }
+
+// End ends the span.
+func (s *recordedSpan) End(...trace.SpanEndOption) { s.ended = true }
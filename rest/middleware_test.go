@@ -0,0 +1,98 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/lvlcn-t/go-kit/executors"
+)
+
+func TestClient_Do_Middleware(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		calls []string
+	)
+	record := func(name string) Middleware {
+		return func(next Doer) Doer {
+			return DoerFunc(func(req *http.Request) (*http.Response, error) {
+				mu.Lock()
+				calls = append(calls, name)
+				mu.Unlock()
+				req = req.Clone(req.Context())
+				req.Header.Add("X-Middleware", name)
+				return next.Do(req)
+			})
+		}
+	}
+
+	tests := []struct {
+		name       string
+		clientOpts []RequestOption
+		opts       []RequestOption
+		statuses   []int
+		wantCalls  []string
+		wantHeader []string
+	}{
+		{
+			name:      "no middleware",
+			statuses:  []int{http.StatusOK},
+			wantCalls: nil,
+		},
+		{
+			name:       "request middlewares in order",
+			opts:       []RequestOption{WithMiddleware(record("first"), record("second"))},
+			statuses:   []int{http.StatusOK},
+			wantCalls:  []string{"first", "second"},
+			wantHeader: []string{"first", "second"},
+		},
+		{
+			name:       "client middlewares run first",
+			clientOpts: []RequestOption{WithMiddleware(record("client"))},
+			opts:       []RequestOption{WithMiddleware(record("request"), nil)},
+			statuses:   []int{http.StatusOK},
+			wantCalls:  []string{"client", "request"},
+			wantHeader: []string{"client", "request"},
+		},
+		{
+			name: "runs for every attempt",
+			opts: []RequestOption{
+				WithMiddleware(record("request")),
+				WithRetry(RetryOptions{MaxRetries: 2, Backoff: executors.ConstantBackoff(time.Millisecond)}),
+			},
+			statuses:   []int{http.StatusServiceUnavailable, http.StatusOK},
+			wantCalls:  []string{"request", "request"},
+			wantHeader: []string{"request"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = nil
+			responder, _ := statusSequence(nil, tt.statuses...)
+			transport := httpmock.NewMockTransport()
+			transport.RegisterResponder(http.MethodGet, "https://example.com/resource", func(req *http.Request) (*http.Response, error) {
+				if got := req.Header.Values("X-Middleware"); !reflect.DeepEqual(got, tt.wantHeader) {
+					t.Errorf("request X-Middleware = %v, want %v", got, tt.wantHeader)
+				}
+				return responder(req)
+			})
+			c := newMockClient(t, transport, tt.clientOpts...)
+
+			status, err := c.Do(context.Background(), Get("/resource"), nil, nil, tt.opts...)
+			if err != nil {
+				t.Fatalf("Client.Do() error = %v", err)
+			}
+			if status != http.StatusOK {
+				t.Errorf("Client.Do() status = %d, want %d", status, http.StatusOK)
+			}
+			if !reflect.DeepEqual(calls, tt.wantCalls) {
+				t.Errorf("middleware calls = %v, want %v", calls, tt.wantCalls)
+			}
+		})
+	}
+}
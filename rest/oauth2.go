@@ -0,0 +1,150 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// TokenSource provides the OAuth2 tokens for the requests of a [Client].
+// It caches the token until it expires and shares it between concurrent requests,
+// so only one request at a time fetches a new token.
+//
+// The token endpoint is not called with the [http.Client] of the [Client], but with the one passed to the constructor
+// of the token source. If it is nil, the [http.Client] of the [oauth2.HTTPClient] context value of the request's context
+// is used, falling back to [http.DefaultClient].
+//
+// Example:
+//
+//	httpClient := &http.Client{Timeout: 10 * time.Second}
+//	ts := rest.ClientCredentialsTokenSource(&clientcredentials.Config{
+//		ClientID:     "id",
+//		ClientSecret: "secret",
+//		TokenURL:     "https://auth.example.com/token",
+//	}, httpClient)
+//	client, err := rest.NewWithClient("https://api.example.com", httpClient, rest.WithTokenSource(ts))
+type TokenSource struct {
+	// fetch returns a new token. The current token is the last fetched one and may be nil.
+	fetch func(ctx context.Context, current *oauth2.Token) (*oauth2.Token, error)
+	// client is the HTTP client that calls the token endpoint. If nil, the one of the context is used.
+	client *http.Client
+	// sem guards the token and lets only one caller fetch a new token.
+	sem chan struct{}
+	// token is the cached token.
+	token *oauth2.Token
+}
+
+// NewTokenSource creates a new [TokenSource] that fetches its tokens from the given source.
+// To replace a token the server rejected before it expired, the source has to return a new token on every call,
+// so don't pass a source that caches its tokens, e.g. one created with [oauth2.ReuseTokenSource].
+func NewTokenSource(src oauth2.TokenSource) *TokenSource {
+	return newTokenSource(nil, nil, func(context.Context, *oauth2.Token) (*oauth2.Token, error) {
+		return src.Token()
+	})
+}
+
+// ClientCredentialsTokenSource creates a new [TokenSource] that fetches its tokens with the OAuth2
+// client credentials flow. The token endpoint is called with the given HTTP client.
+func ClientCredentialsTokenSource(cfg *clientcredentials.Config, client *http.Client) *TokenSource {
+	return newTokenSource(nil, client, func(ctx context.Context, _ *oauth2.Token) (*oauth2.Token, error) {
+		return cfg.Token(ctx)
+	})
+}
+
+// RefreshTokenSource creates a new [TokenSource] that starts with the given token and fetches new tokens
+// with its refresh token. The refresh token is replaced if the server returns a new one.
+// The token endpoint is called with the given HTTP client.
+func RefreshTokenSource(cfg *oauth2.Config, token *oauth2.Token, client *http.Client) *TokenSource {
+	return newTokenSource(token, client, func(ctx context.Context, current *oauth2.Token) (*oauth2.Token, error) {
+		if current == nil || current.RefreshToken == "" {
+			return nil, errors.New("no refresh token")
+		}
+		// A token without an access token is invalid, so the source always refreshes it.
+		return cfg.TokenSource(ctx, &oauth2.Token{RefreshToken: current.RefreshToken}).Token()
+	})
+}
+
+// newTokenSource creates a new [TokenSource] with the initial token, the HTTP client and the fetch function.
+func newTokenSource(token *oauth2.Token, client *http.Client, fetch func(context.Context, *oauth2.Token) (*oauth2.Token, error)) *TokenSource {
+	return &TokenSource{
+		fetch:  fetch,
+		client: client,
+		sem:    make(chan struct{}, 1),
+		token:  token,
+	}
+}
+
+// Token returns the cached token or fetches a new one if the cached token is missing or expired.
+func (s *TokenSource) Token(ctx context.Context) (*oauth2.Token, error) {
+	return s.get(ctx, nil)
+}
+
+// get returns the cached token or fetches a new one if the cached token is invalid or the rejected one.
+// If another caller already replaced the rejected token, its replacement is returned.
+func (s *TokenSource) get(ctx context.Context, rejected *oauth2.Token) (*oauth2.Token, error) {
+	select {
+	case s.sem <- struct{}{}:
+		defer func() { <-s.sem }()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if s.token.Valid() && s.token != rejected {
+		return s.token, nil
+	}
+	if s.client != nil {
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, s.client)
+	}
+	token, err := s.fetch(ctx, s.token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch token: %w", err)
+	}
+	s.token = token
+	return token, nil
+}
+
+// WithTokenSource is a request option that authorizes the request with a token of the [TokenSource].
+// Use it as option of [NewWithClient] to authorize all requests of a client.
+//
+// If the server responds with [http.StatusUnauthorized], the token is replaced and the request is retried once,
+// provided its body can be rebuilt. If the token can't be replaced, the unauthorized response is returned.
+//
+// The tokens are fetched with the HTTP client of the [TokenSource], not with the one of the [Client].
+func WithTokenSource(ts *TokenSource) RequestOption {
+	return WithMiddleware(func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			token, err := ts.Token(req.Context())
+			if err != nil {
+				return nil, err
+			}
+			resp, err := next.Do(authorize(req, token))
+			if err != nil || resp.StatusCode != http.StatusUnauthorized || !canRebuildBody(req) {
+				return resp, err
+			}
+
+			// The token may have been revoked before it expired, so the request is retried once with a new token.
+			// If that isn't possible, the unauthorized response is the final one.
+			retry, err := rebuild(req)
+			if err != nil {
+				return resp, nil
+			}
+			token, err = ts.get(req.Context(), token)
+			if err != nil {
+				return resp, nil
+			}
+			discard(resp)
+			return next.Do(authorize(retry, token))
+		})
+	})
+}
+
+// authorize returns a clone of the request with the authorization header of the token.
+func authorize(req *http.Request, token *oauth2.Token) *http.Request {
+	req = req.Clone(req.Context())
+	token.SetAuthHeader(req)
+	return req
+}
@@ -0,0 +1,282 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// tokenEndpoint returns a responder that issues numbered access tokens and records the grant types of the requests.
+func tokenEndpoint() (httpmock.Responder, func() []string) {
+	var (
+		mu     sync.Mutex
+		grants []string
+	)
+	responder := func(req *http.Request) (*http.Response, error) {
+		if err := req.ParseForm(); err != nil {
+			return nil, err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		grants = append(grants, req.PostForm.Get("grant_type"))
+		resp := httpmock.NewStringResponse(http.StatusOK,
+			fmt.Sprintf(`{"access_token":"token-%d","token_type":"Bearer","expires_in":3600}`, len(grants)))
+		resp.Header.Set("Content-Type", "application/json")
+		return resp, nil
+	}
+	return responder, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return grants
+	}
+}
+
+// rejectTokens returns a responder that answers requests authorized with one of the rejected tokens with
+// [http.StatusUnauthorized] and records the authorization headers and bodies of the requests.
+func rejectTokens(rejected ...string) (httpmock.Responder, func() []string) {
+	var (
+		mu       sync.Mutex
+		requests []string
+	)
+	responder := func(req *http.Request) (*http.Response, error) {
+		var body []byte
+		if req.Body != nil {
+			body, _ = io.ReadAll(req.Body)
+		}
+		auth := req.Header.Get("Authorization")
+		mu.Lock()
+		requests = append(requests, strings.TrimSpace(auth+" "+string(body)))
+		mu.Unlock()
+
+		for _, token := range rejected {
+			if auth == "Bearer "+token {
+				return httpmock.NewStringResponse(http.StatusUnauthorized, ""), nil
+			}
+		}
+		return httpmock.NewStringResponse(http.StatusOK, `{"id":1,"name":"Resource"}`), nil
+	}
+	return responder, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return requests
+	}
+}
+
+func TestClient_Do_TokenSource(t *testing.T) {
+	tests := []struct {
+		name         string
+		tokenSource  func(client *http.Client) *TokenSource
+		rejected     []string
+		requests     int
+		wantStatus   int
+		wantGrants   []string
+		wantRequests []string
+	}{
+		{
+			name: "client credentials are cached",
+			tokenSource: func(client *http.Client) *TokenSource {
+				return ClientCredentialsTokenSource(&clientcredentials.Config{
+					ClientID:     "id",
+					ClientSecret: "secret",
+					TokenURL:     "https://auth.example.com/token",
+				}, client)
+			},
+			requests:     2,
+			wantStatus:   http.StatusOK,
+			wantGrants:   []string{"client_credentials"},
+			wantRequests: []string{"Bearer token-1 payload", "Bearer token-1 payload"},
+		},
+		{
+			name: "expired token is refreshed",
+			tokenSource: func(client *http.Client) *TokenSource {
+				return RefreshTokenSource(&oauth2.Config{
+					ClientID: "id",
+					Endpoint: oauth2.Endpoint{TokenURL: "https://auth.example.com/token"},
+				}, &oauth2.Token{AccessToken: "expired", RefreshToken: "refresh", Expiry: time.Now().Add(-time.Minute)}, client)
+			},
+			requests:     1,
+			wantStatus:   http.StatusOK,
+			wantGrants:   []string{"refresh_token"},
+			wantRequests: []string{"Bearer token-1 payload"},
+		},
+		{
+			name: "valid token is used",
+			tokenSource: func(client *http.Client) *TokenSource {
+				return RefreshTokenSource(&oauth2.Config{
+					ClientID: "id",
+					Endpoint: oauth2.Endpoint{TokenURL: "https://auth.example.com/token"},
+				}, &oauth2.Token{AccessToken: "valid", RefreshToken: "refresh", Expiry: time.Now().Add(time.Hour)}, client)
+			},
+			requests:     1,
+			wantStatus:   http.StatusOK,
+			wantRequests: []string{"Bearer valid payload"},
+		},
+		{
+			name: "rejected token is replaced",
+			tokenSource: func(client *http.Client) *TokenSource {
+				return RefreshTokenSource(&oauth2.Config{
+					ClientID: "id",
+					Endpoint: oauth2.Endpoint{TokenURL: "https://auth.example.com/token"},
+				}, &oauth2.Token{AccessToken: "revoked", RefreshToken: "refresh", Expiry: time.Now().Add(time.Hour)}, client)
+			},
+			rejected:     []string{"revoked"},
+			requests:     2,
+			wantStatus:   http.StatusOK,
+			wantGrants:   []string{"refresh_token"},
+			wantRequests: []string{"Bearer revoked payload", "Bearer token-1 payload", "Bearer token-1 payload"},
+		},
+		{
+			name: "retried only once",
+			tokenSource: func(client *http.Client) *TokenSource {
+				return ClientCredentialsTokenSource(&clientcredentials.Config{
+					ClientID:     "id",
+					ClientSecret: "secret",
+					TokenURL:     "https://auth.example.com/token",
+				}, client)
+			},
+			rejected:     []string{"token-1", "token-2"},
+			requests:     1,
+			wantStatus:   http.StatusUnauthorized,
+			wantGrants:   []string{"client_credentials", "client_credentials"},
+			wantRequests: []string{"Bearer token-1 payload", "Bearer token-2 payload"},
+		},
+		{
+			name: "rejected response is returned if the token can't be replaced",
+			tokenSource: func(client *http.Client) *TokenSource {
+				return RefreshTokenSource(&oauth2.Config{
+					ClientID: "id",
+					Endpoint: oauth2.Endpoint{TokenURL: "https://auth.example.com/token"},
+				}, &oauth2.Token{AccessToken: "revoked", Expiry: time.Now().Add(time.Hour)}, client)
+			},
+			rejected:     []string{"revoked"},
+			requests:     1,
+			wantStatus:   http.StatusUnauthorized,
+			wantRequests: []string{"Bearer revoked payload"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := httpmock.NewMockTransport()
+			tokens, grants := tokenEndpoint()
+			transport.RegisterResponder(http.MethodPost, "https://auth.example.com/token", tokens)
+			api, requests := rejectTokens(tt.rejected...)
+			transport.RegisterResponder(http.MethodPut, "https://example.com/resource", api)
+			c := newMockClient(t, transport, WithTokenSource(tt.tokenSource(&http.Client{Transport: transport})))
+
+			for range tt.requests {
+				status, err := c.Do(context.Background(), Put("/resource"), "payload", nil, WithCodec(RawCodec))
+				if err != nil {
+					t.Fatalf("Client.Do() error = %v", err)
+				}
+				if status != tt.wantStatus {
+					t.Errorf("Client.Do() status = %d, want %d", status, tt.wantStatus)
+				}
+			}
+
+			if got := grants(); fmt.Sprint(got) != fmt.Sprint(tt.wantGrants) {
+				t.Errorf("token requests = %v, want %v", got, tt.wantGrants)
+			}
+			if got := requests(); fmt.Sprint(got) != fmt.Sprint(tt.wantRequests) {
+				t.Errorf("requests = %q, want %q", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestTokenSource_Concurrent(t *testing.T) {
+	var fetches atomic.Int32
+	ts := NewTokenSource(tokenSourceFunc(func() (*oauth2.Token, error) {
+		n := fetches.Add(1)
+		time.Sleep(10 * time.Millisecond)
+		return &oauth2.Token{AccessToken: fmt.Sprintf("token-%d", n), Expiry: time.Now().Add(time.Hour)}, nil
+	}))
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			token, err := ts.Token(context.Background())
+			if err != nil {
+				t.Errorf("TokenSource.Token() error = %v", err)
+				return
+			}
+			if token.AccessToken != "token-1" {
+				t.Errorf("TokenSource.Token() = %q, want %q", token.AccessToken, "token-1")
+			}
+		})
+	}
+	wg.Wait()
+
+	// Concurrent requests rejecting the same token replace it only once.
+	rejected, _ := ts.Token(context.Background())
+	for range 10 {
+		wg.Go(func() {
+			if _, err := ts.get(context.Background(), rejected); err != nil {
+				t.Errorf("TokenSource.get() error = %v", err)
+			}
+		})
+	}
+	wg.Wait()
+
+	if got := fetches.Load(); got != 2 {
+		t.Errorf("TokenSource fetched %d tokens, want 2", got)
+	}
+}
+
+func TestTokenSource_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		ts   *TokenSource
+		ctx  func() context.Context
+	}{
+		{
+			name: "source error",
+			ts: NewTokenSource(tokenSourceFunc(func() (*oauth2.Token, error) {
+				return nil, fmt.Errorf("unavailable")
+			})),
+			ctx: context.Background,
+		},
+		{
+			name: "no refresh token",
+			ts:   RefreshTokenSource(&oauth2.Config{}, &oauth2.Token{AccessToken: "expired", Expiry: time.Now().Add(-time.Minute)}, nil),
+			ctx:  context.Background,
+		},
+		{
+			name: "canceled context",
+			ts: func() *TokenSource {
+				ts := NewTokenSource(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"}))
+				ts.sem <- struct{}{}
+				return ts
+			}(),
+			ctx: func() context.Context {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.ts.Token(tt.ctx()); err == nil {
+				t.Error("TokenSource.Token() error = nil, want error")
+			}
+		})
+	}
+}
+
+// tokenSourceFunc is an adapter to use a function as [oauth2.TokenSource].
+type tokenSourceFunc func() (*oauth2.Token, error)
+
+// Token calls the function.
+func (f tokenSourceFunc) Token() (*oauth2.Token, error) { return f() }
@@ -0,0 +1,277 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/lvlcn-t/go-kit/executors"
+)
+
+const (
+	// defaultMaxRetries is the default maximum number of attempts of a request.
+	defaultMaxRetries = 3
+	// defaultRetryBaseDelay is the base delay of the default backoff.
+	defaultRetryBaseDelay = 100 * time.Millisecond
+	// defaultRetryMaxDelay is the maximum delay of the default backoff.
+	defaultRetryMaxDelay = 10 * time.Second
+	// defaultMaxRetryAfter is the default maximum duration of a Retry-After header that is waited for.
+	defaultMaxRetryAfter = time.Minute
+)
+
+// RetryOptions configures the retries of a request.
+type RetryOptions struct {
+	// MaxRetries is the maximum number of attempts. Defaults to 3.
+	MaxRetries int
+	// Backoff calculates the delay before a retry. Defaults to [executors.FullJitterBackoff] of 100ms up to 10s.
+	// A Retry-After header of the response takes precedence.
+	Backoff executors.Backoff
+	// RetryIf reports whether a request is retried after it returned the response or failed with the error.
+	// Defaults to [DefaultRetryIf].
+	RetryIf func(resp *http.Response, err error) bool
+	// RetryNonIdempotent allows retrying requests with the non-idempotent methods POST and PATCH.
+	// Only enable it if the server deduplicates the requests, e.g. with an idempotency key.
+	RetryNonIdempotent bool
+	// MaxRetryAfter is the longest Retry-After header that is waited for.
+	// The response is returned without retrying if the server asks to wait longer. Defaults to one minute.
+	MaxRetryAfter time.Duration
+	// Budget is the retry budget consumed by each retry. It can be shared between many requests.
+	// A nil budget allows unlimited retries.
+	Budget *executors.RetryBudget
+	// Observer is notified before every retry.
+	Observer executors.Observer
+}
+
+// WithRetry is a request option that retries the request according to the given options.
+// Use it as option of [NewWithClient] to retry all requests of a client.
+//
+// Only requests with an idempotent method are retried unless RetryNonIdempotent is set.
+// The request body is rebuilt for every attempt, so requests whose body was replaced by an option without setting
+// [http.Request.GetBody] are made only once.
+func WithRetry(opts RetryOptions) RequestOption {
+	return func(r *Request) {
+		r.Retry = &opts
+	}
+}
+
+// WithCircuitBreaker is a request option that makes the request through the given circuit breaker.
+// Use it as option of [NewWithClient] to share the breaker between all requests of a client.
+//
+// Transport errors and responses that would be retried count as failures,
+// which are those the RetryIf of the [RetryOptions] or otherwise [DefaultRetryIf] reports true for.
+// While the circuit is open, requests fail with [executors.ErrCircuitOpen].
+func WithCircuitBreaker(breaker *executors.Breaker) RequestOption {
+	return func(r *Request) {
+		r.Breaker = breaker
+	}
+}
+
+// DefaultRetryIf is the default classifier of [RetryOptions].
+// It retries transport errors, except for canceled requests, and responses with a 5xx or 429 status code.
+func DefaultRetryIf(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	return resp != nil && (resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests)
+}
+
+// withDefaults returns the options with defaults for all unset fields.
+func (o RetryOptions) withDefaults() RetryOptions {
+	if o.MaxRetries < 1 {
+		o.MaxRetries = defaultMaxRetries
+	}
+	if o.Backoff == nil {
+		o.Backoff = executors.FullJitterBackoff(defaultRetryBaseDelay, defaultRetryMaxDelay, nil)
+	}
+	if o.RetryIf == nil {
+		o.RetryIf = DefaultRetryIf
+	}
+	if o.MaxRetryAfter <= 0 {
+		o.MaxRetryAfter = defaultMaxRetryAfter
+	}
+	return o
+}
+
+// errRetryableStatus is returned by an attempt whose response should be retried.
+// It lets the retrier and the circuit breaker treat the response as failure.
+type errRetryableStatus struct {
+	// status is the status code of the response.
+	status int
+	// final is set if the response is not retried anyway, because its Retry-After header exceeds the maximum.
+	// Unlike a [executors.Permanent] error, it still counts as failure of the circuit breaker.
+	final bool
+}
+
+// Error returns the error message.
+func (e *errRetryableStatus) Error() string {
+	return fmt.Sprintf("retryable status code %d", e.status)
+}
+
+// roundTrip makes the request with the retries and the circuit breaker of the request.
+// Returns the last response, whose body must be closed by the caller.
+func (r *restClient) roundTrip(ctx context.Context, request *Request) (*http.Response, error) {
+	opts := RetryOptions{MaxRetries: 1, RetryIf: DefaultRetryIf, MaxRetryAfter: defaultMaxRetryAfter}
+	if request.Retry != nil {
+		opts = request.Retry.withDefaults()
+	}
+	if (!isIdempotent(request.Http.Method) && !opts.RetryNonIdempotent) || !canRebuildBody(request.Http) {
+		opts.MaxRetries = 1
+	}
+
+	// The rate limit applies to the request, so its retries don't wait for the limiter again.
+	if err := r.limiter.Wait(ctx); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, ErrRateLimitExceeded
+	}
+
+	doer := chain(r.client, request.Middlewares)
+	var (
+		resp       *http.Response
+		attempts   int
+		retryAfter time.Duration
+	)
+	effector := executors.Effector(func(ctx context.Context) error {
+		// The response of the previous attempt is dropped, since the request is retried.
+		discard(resp)
+		resp, retryAfter = nil, 0
+
+		req, err := request.attempt(attempts)
+		attempts++
+		if err != nil {
+			return executors.Permanent(err)
+		}
+
+		res, err := doer.Do(req) // #nosec G704 // URL is controlled by the caller and should be validated before being passed to the client.
+		if err != nil {
+			err = fmt.Errorf("failed to make request: %w", err)
+			if !opts.RetryIf(nil, err) {
+				return executors.Permanent(err)
+			}
+			return err
+		}
+		resp = res
+
+		if !opts.RetryIf(res, nil) {
+			return nil
+		}
+		err = &errRetryableStatus{status: res.StatusCode}
+		if d, ok := parseRetryAfter(res.Header.Get("Retry-After"), time.Now()); ok {
+			if d > opts.MaxRetryAfter {
+				return &errRetryableStatus{status: res.StatusCode, final: true}
+			}
+			retryAfter = d
+		}
+		return err
+	})
+
+	if request.Breaker != nil {
+		effector = request.Breaker.Wrap(effector)
+	}
+	if opts.MaxRetries > 1 {
+		retrier := &executors.Retrier{
+			MaxRetries: opts.MaxRetries,
+			Backoff: func(retries uint) time.Duration {
+				if retryAfter > 0 {
+					return retryAfter
+				}
+				return opts.Backoff(retries)
+			},
+			RetryIf: func(err error) bool {
+				var status *errRetryableStatus
+				if errors.As(err, &status) && status.final {
+					return false
+				}
+				return executors.DefaultRetryIf(err) && !errors.As(err, &executors.ErrCircuitOpen{})
+			},
+			Budget:   opts.Budget,
+			Observer: opts.Observer,
+		}
+		effector = retrier.Retry(effector)
+	}
+
+	err := effector(ctx)
+	var status *errRetryableStatus
+	if err == nil || errors.As(err, &status) {
+		return resp, nil
+	}
+	// If the circuit opened after a retryable response, the response is the final one and is returned as such.
+	if resp != nil && errors.As(err, &executors.ErrCircuitOpen{}) {
+		return resp, nil
+	}
+	discard(resp)
+	var permanent *executors.ErrPermanent
+	if errors.As(err, &permanent) {
+		return nil, permanent.Err
+	}
+	return nil, err
+}
+
+// attempt returns the request for the attempt with the given zero-based number.
+// Every retry gets a fresh body, because the body of the previous attempt has been consumed.
+func (r *Request) attempt(n int) (*http.Request, error) {
+	if n == 0 {
+		return r.Http, nil
+	}
+	return rebuild(r.Http)
+}
+
+// rebuild returns a clone of the request with a fresh body, so the request can be sent again.
+func rebuild(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("failed to rebuild request body: %w", err)
+		}
+		clone.Body = body
+	}
+	return clone, nil
+}
+
+// canRebuildBody reports whether the body of the request can be rebuilt for a retry.
+func canRebuildBody(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// isIdempotent reports whether the method is idempotent.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPatch:
+		return false
+	default:
+		return true
+	}
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either a number of seconds or an HTTP date.
+// Returns false if the value is empty or invalid.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// discard drains and closes the body of the response, so its connection can be reused.
+func discard(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxDiscardBytes))
+	_ = resp.Body.Close()
+}
+
+// maxDiscardBytes is the maximum number of bytes read from a discarded response body to reuse its connection.
+const maxDiscardBytes = 4 << 10
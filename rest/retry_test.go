@@ -0,0 +1,440 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/lvlcn-t/go-kit/executors"
+	"golang.org/x/time/rate"
+)
+
+// newMockClient creates a rest client whose requests are answered by the transport.
+func newMockClient(t *testing.T, transport *httpmock.MockTransport, opts ...RequestOption) Client {
+	t.Helper()
+	c, err := NewWithClient("https://example.com", &http.Client{Transport: transport}, opts...)
+	if err != nil {
+		t.Fatalf("NewWithClient() error = %v", err)
+	}
+	return c
+}
+
+// statusSequence returns a responder that answers with the given status codes in order
+// and records the bodies of the requests. The last status code is repeated.
+func statusSequence(header http.Header, codes ...int) (httpmock.Responder, func() []string) {
+	var (
+		mu     sync.Mutex
+		bodies []string
+	)
+	responder := func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		var body []byte
+		if req.Body != nil {
+			body, _ = io.ReadAll(req.Body)
+		}
+		bodies = append(bodies, string(body))
+
+		resp := httpmock.NewStringResponse(codes[min(len(bodies), len(codes))-1], `{"id":1,"name":"Resource"}`)
+		for key, values := range header {
+			resp.Header[key] = values
+		}
+		return resp, nil
+	}
+	return responder, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return bodies
+	}
+}
+
+func TestClient_Do_Retry(t *testing.T) {
+	fastRetry := RetryOptions{MaxRetries: 3, Backoff: executors.ConstantBackoff(time.Millisecond)}
+	tests := []struct {
+		name         string
+		endpoint     *Endpoint
+		payload      any
+		clientOpts   []RequestOption
+		opts         []RequestOption
+		header       http.Header
+		codes        []int
+		wantCode     int
+		wantAttempts int
+	}{
+		{
+			name:         "no retry",
+			endpoint:     Get("/resource"),
+			codes:        []int{http.StatusServiceUnavailable, http.StatusOK},
+			wantCode:     http.StatusServiceUnavailable,
+			wantAttempts: 1,
+		},
+		{
+			name:         "server error is retried",
+			endpoint:     Get("/resource"),
+			opts:         []RequestOption{WithRetry(fastRetry)},
+			codes:        []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusOK},
+			wantCode:     http.StatusOK,
+			wantAttempts: 3,
+		},
+		{
+			name:         "too many requests is retried",
+			endpoint:     Get("/resource"),
+			opts:         []RequestOption{WithRetry(fastRetry)},
+			codes:        []int{http.StatusTooManyRequests, http.StatusOK},
+			wantCode:     http.StatusOK,
+			wantAttempts: 2,
+		},
+		{
+			name:         "retries exhausted",
+			endpoint:     Get("/resource"),
+			opts:         []RequestOption{WithRetry(fastRetry)},
+			codes:        []int{http.StatusServiceUnavailable},
+			wantCode:     http.StatusServiceUnavailable,
+			wantAttempts: 3,
+		},
+		{
+			name:         "client error is not retried",
+			endpoint:     Get("/resource"),
+			opts:         []RequestOption{WithRetry(fastRetry)},
+			codes:        []int{http.StatusNotFound, http.StatusOK},
+			wantCode:     http.StatusNotFound,
+			wantAttempts: 1,
+		},
+		{
+			name:         "client level retry",
+			endpoint:     Delete("/resource"),
+			clientOpts:   []RequestOption{WithRetry(fastRetry)},
+			codes:        []int{http.StatusInternalServerError, http.StatusOK},
+			wantCode:     http.StatusOK,
+			wantAttempts: 2,
+		},
+		{
+			name:         "request overrides client level retry",
+			endpoint:     Get("/resource"),
+			clientOpts:   []RequestOption{WithRetry(fastRetry)},
+			opts:         []RequestOption{WithRetry(RetryOptions{MaxRetries: 1})},
+			codes:        []int{http.StatusInternalServerError, http.StatusOK},
+			wantCode:     http.StatusInternalServerError,
+			wantAttempts: 1,
+		},
+		{
+			name:         "post is not retried",
+			endpoint:     Post("/resource"),
+			payload:      map[string]string{"name": "Resource"},
+			opts:         []RequestOption{WithRetry(fastRetry)},
+			codes:        []int{http.StatusInternalServerError, http.StatusOK},
+			wantCode:     http.StatusInternalServerError,
+			wantAttempts: 1,
+		},
+		{
+			name:     "post is retried if opted in",
+			endpoint: Post("/resource"),
+			payload:  map[string]string{"name": "Resource"},
+			opts: []RequestOption{WithRetry(RetryOptions{
+				MaxRetries:         3,
+				Backoff:            executors.ConstantBackoff(time.Millisecond),
+				RetryNonIdempotent: true,
+			})},
+			codes:        []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusCreated},
+			wantCode:     http.StatusCreated,
+			wantAttempts: 3,
+		},
+		{
+			name:     "body that can't be rebuilt is not retried",
+			endpoint: Put("/resource"),
+			opts: []RequestOption{WithRetry(fastRetry), func(r *Request) {
+				r.Http.Body = io.NopCloser(strings.NewReader("stream"))
+				r.Http.GetBody = nil
+			}},
+			codes:        []int{http.StatusInternalServerError, http.StatusOK},
+			wantCode:     http.StatusInternalServerError,
+			wantAttempts: 1,
+		},
+		{
+			name:         "too long retry after is not waited for",
+			endpoint:     Get("/resource"),
+			opts:         []RequestOption{WithRetry(RetryOptions{MaxRetries: 3, MaxRetryAfter: time.Second})},
+			header:       http.Header{"Retry-After": []string{"120"}},
+			codes:        []int{http.StatusServiceUnavailable, http.StatusOK},
+			wantCode:     http.StatusServiceUnavailable,
+			wantAttempts: 1,
+		},
+		{
+			name:     "custom retry if",
+			endpoint: Get("/resource"),
+			opts: []RequestOption{WithRetry(RetryOptions{
+				MaxRetries: 3,
+				Backoff:    executors.ConstantBackoff(time.Millisecond),
+				RetryIf: func(resp *http.Response, err error) bool {
+					return err == nil && resp.StatusCode == http.StatusConflict
+				},
+			})},
+			codes:        []int{http.StatusConflict, http.StatusInternalServerError},
+			wantCode:     http.StatusInternalServerError,
+			wantAttempts: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := httpmock.NewMockTransport()
+			responder, bodies := statusSequence(tt.header, tt.codes...)
+			transport.RegisterResponder(tt.endpoint.Method, "https://example.com/resource", responder)
+			c := newMockClient(t, transport, tt.clientOpts...)
+
+			var got response
+			code, err := c.Do(context.Background(), tt.endpoint, tt.payload, &got, tt.opts...)
+			if err != nil {
+				t.Errorf("Client.Do() error = %v", err)
+			}
+			if code != tt.wantCode {
+				t.Errorf("Client.Do() code = %v, want %v", code, tt.wantCode)
+			}
+
+			gotBodies := bodies()
+			if len(gotBodies) != tt.wantAttempts {
+				t.Errorf("Client.Do() attempts = %d, want %d", len(gotBodies), tt.wantAttempts)
+			}
+			for i, body := range gotBodies {
+				if body != gotBodies[0] {
+					t.Errorf("Client.Do() body of attempt %d = %q, want %q", i+1, body, gotBodies[0])
+				}
+			}
+		})
+	}
+}
+
+func TestClient_Do_Retry_TransportError(t *testing.T) {
+	transport := httpmock.NewMockTransport()
+	attempts := 0
+	transport.RegisterResponder(http.MethodGet, "https://example.com/resource", func(*http.Request) (*http.Response, error) {
+		attempts++
+		return nil, errors.New("connection reset")
+	})
+	c := newMockClient(t, transport, WithRetry(RetryOptions{MaxRetries: 2, Backoff: executors.ConstantBackoff(time.Millisecond)}))
+
+	_, err := c.Do(context.Background(), Get("/resource"), nil, nil)
+	if err == nil {
+		t.Error("Client.Do() error = nil, want error")
+	}
+	if attempts != 2 {
+		t.Errorf("Client.Do() attempts = %d, want 2", attempts)
+	}
+}
+
+func TestClient_Do_Retry_RetryAfter(t *testing.T) {
+	transport := httpmock.NewMockTransport()
+	responder, bodies := statusSequence(http.Header{"Retry-After": []string{"1"}}, http.StatusServiceUnavailable, http.StatusOK)
+	transport.RegisterResponder(http.MethodGet, "https://example.com/resource", responder)
+	c := newMockClient(t, transport, WithRetry(RetryOptions{Backoff: executors.ConstantBackoff(time.Millisecond)}))
+
+	start := time.Now()
+	code, err := c.Do(context.Background(), Get("/resource"), nil, nil)
+	if err != nil || code != http.StatusOK {
+		t.Fatalf("Client.Do() = %v, %v, want %v", code, err, http.StatusOK)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("Client.Do() retried after %v, want at least 1s", elapsed)
+	}
+	if got := len(bodies()); got != 2 {
+		t.Errorf("Client.Do() attempts = %d, want 2", got)
+	}
+}
+
+func TestClient_Do_CircuitBreaker(t *testing.T) {
+	transport := httpmock.NewMockTransport()
+	responder, bodies := statusSequence(nil, http.StatusInternalServerError)
+	transport.RegisterResponder(http.MethodGet, "https://example.com/resource", responder)
+	breaker := executors.NewBreaker(executors.BreakerOptions{MaxFailures: 2, ResetTimeout: time.Minute})
+	c := newMockClient(t, transport, WithCircuitBreaker(breaker))
+
+	for range 3 {
+		_, _ = c.Do(context.Background(), Get("/resource"), nil, nil)
+	}
+	if got := len(bodies()); got != 2 {
+		t.Errorf("Client.Do() attempts = %d, want 2", got)
+	}
+	if got := breaker.State(); got != executors.StateOpen {
+		t.Errorf("Breaker.State() = %v, want %v", got, executors.StateOpen)
+	}
+
+	_, err := c.Do(context.Background(), Get("/resource"), nil, nil)
+	if !errors.As(err, &executors.ErrCircuitOpen{}) {
+		t.Errorf("Client.Do() error = %v, want %v", err, executors.ErrCircuitOpen{})
+	}
+}
+
+func TestClient_Do_CircuitBreaker_Retry(t *testing.T) {
+	transport := httpmock.NewMockTransport()
+	responder, bodies := statusSequence(nil, http.StatusServiceUnavailable)
+	transport.RegisterResponder(http.MethodGet, "https://example.com/resource", responder)
+	breaker := executors.NewBreaker(executors.BreakerOptions{MaxFailures: 2, ResetTimeout: time.Minute})
+	c := newMockClient(t, transport,
+		WithCircuitBreaker(breaker),
+		WithRetry(RetryOptions{MaxRetries: 5, Backoff: executors.ConstantBackoff(time.Millisecond)}),
+	)
+
+	// The response that opened the circuit is the final response of the request.
+	code, err := c.Do(context.Background(), Get("/resource"), nil, nil)
+	if err != nil || code != http.StatusServiceUnavailable {
+		t.Errorf("Client.Do() = %v, %v, want %v", code, err, http.StatusServiceUnavailable)
+	}
+	if got := len(bodies()); got != 2 {
+		t.Errorf("Client.Do() attempts = %d, want 2", got)
+	}
+	if got := breaker.State(); got != executors.StateOpen {
+		t.Errorf("Breaker.State() = %v, want %v", got, executors.StateOpen)
+	}
+}
+
+func TestClient_Do_CircuitBreaker_RetryAfter(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []RequestOption
+	}{
+		{
+			name: "without retries",
+		},
+		{
+			name: "with retries",
+			opts: []RequestOption{WithRetry(RetryOptions{MaxRetries: 5, Backoff: executors.ConstantBackoff(time.Millisecond)})},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := httpmock.NewMockTransport()
+			responder, bodies := statusSequence(http.Header{"Retry-After": []string{"120"}}, http.StatusServiceUnavailable)
+			transport.RegisterResponder(http.MethodGet, "https://example.com/resource", responder)
+			breaker := executors.NewBreaker(executors.BreakerOptions{MaxFailures: 2, ResetTimeout: time.Minute})
+			c := newMockClient(t, transport, append(tt.opts, WithCircuitBreaker(breaker))...)
+
+			// A Retry-After header exceeding the maximum stops the retries but still counts as failure.
+			for range 2 {
+				code, err := c.Do(context.Background(), Get("/resource"), nil, nil)
+				if err != nil || code != http.StatusServiceUnavailable {
+					t.Errorf("Client.Do() = %v, %v, want %v", code, err, http.StatusServiceUnavailable)
+				}
+			}
+			if got := len(bodies()); got != 2 {
+				t.Errorf("Client.Do() attempts = %d, want 2", got)
+			}
+			if got := breaker.State(); got != executors.StateOpen {
+				t.Errorf("Breaker.State() = %v, want %v", got, executors.StateOpen)
+			}
+		})
+	}
+}
+
+func TestClient_Do_RateLimit(t *testing.T) {
+	tests := []struct {
+		name         string
+		ctx          func() (context.Context, context.CancelFunc)
+		wantErr      error
+		wantAttempts int
+	}{
+		{
+			name:         "retries don't wait for the limiter",
+			ctx:          func() (context.Context, context.CancelFunc) { return context.Background(), func() {} },
+			wantAttempts: 3,
+		},
+		{
+			name: "deadline before the next token",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), time.Second)
+			},
+			wantErr: ErrRateLimitExceeded,
+		},
+		{
+			name: "canceled context",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx, cancel
+			},
+			wantErr: context.Canceled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := httpmock.NewMockTransport()
+			responder, bodies := statusSequence(nil, http.StatusServiceUnavailable)
+			transport.RegisterResponder(http.MethodGet, "https://example.com/resource", responder)
+			c := newMockClient(t, transport, WithRetry(RetryOptions{MaxRetries: 3, Backoff: executors.ConstantBackoff(time.Millisecond)}))
+			// The limiter allows a single request per hour.
+			c.RateLimiter().SetLimit(rate.Every(time.Hour))
+			c.RateLimiter().SetBurst(1)
+			if tt.wantErr != nil {
+				_ = c.RateLimiter().Allow()
+			}
+
+			ctx, cancel := tt.ctx()
+			defer cancel()
+			_, err := c.Do(ctx, Get("/resource"), nil, nil)
+			if !errors.Is(err, tt.wantErr) || (err != nil) != (tt.wantErr != nil) {
+				t.Errorf("Client.Do() error = %v, want %v", err, tt.wantErr)
+			}
+			if got := len(bodies()); got != tt.wantAttempts {
+				t.Errorf("Client.Do() attempts = %d, want %d", got, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestDefaultRetryIf(t *testing.T) {
+	tests := []struct {
+		name string
+		resp *http.Response
+		err  error
+		want bool
+	}{
+		{name: "transport error", err: errors.New("connection reset"), want: true},
+		{name: "canceled", err: context.Canceled, want: false},
+		{name: "deadline exceeded", err: context.DeadlineExceeded, want: false},
+		{name: "ok", resp: &http.Response{StatusCode: http.StatusOK}, want: false},
+		{name: "bad request", resp: &http.Response{StatusCode: http.StatusBadRequest}, want: false},
+		{name: "too many requests", resp: &http.Response{StatusCode: http.StatusTooManyRequests}, want: true},
+		{name: "service unavailable", resp: &http.Response{StatusCode: http.StatusServiceUnavailable}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DefaultRetryIf(tt.resp, tt.err); got != tt.want {
+				t.Errorf("DefaultRetryIf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		value  string
+		want   time.Duration
+		wantOk bool
+	}{
+		{name: "empty", value: "", wantOk: false},
+		{name: "seconds", value: "120", want: 2 * time.Minute, wantOk: true},
+		{name: "negative seconds", value: "-1", want: 0, wantOk: true},
+		{name: "http date", value: now.Add(30 * time.Second).Format(http.TimeFormat), want: 30 * time.Second, wantOk: true},
+		{name: "past http date", value: now.Add(-time.Hour).Format(http.TimeFormat), want: 0, wantOk: true},
+		{name: "invalid", value: "soon", wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseRetryAfter(tt.value, now)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("parseRetryAfter() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}